@@ -0,0 +1,141 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"suftsdk/internal/auth"
+	"suftsdk/pkg/api"
+)
+
+// коды завершения CLI
+const (
+	exitCodeError        int = 1
+	exitCodeUnauthorized int = 3
+	exitCodeForbidden    int = 4
+	exitCodeNotFound     int = 5
+	exitCodeConflict     int = 6
+	exitCodeServerError  int = 7
+)
+
+// названия действий для сообщений об ошибках статуса
+var actionLabels = map[api.Action]string{
+	api.ActionSubmit:  "отправка на утверждение",
+	api.ActionEdit:    "изменение временных затрат",
+	api.ActionApprove: "утверждение",
+	api.ActionDecline: "отклонение",
+}
+
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, api.ErrUnauthorized):
+		return exitCodeUnauthorized
+	case errors.Is(err, api.ErrForbidden):
+		return exitCodeForbidden
+	case errors.Is(err, api.ErrNotFound):
+		return exitCodeNotFound
+	case errors.Is(err, api.ErrConflict):
+		return exitCodeConflict
+	}
+	apiErr := &api.APIError{}
+	if errors.As(err, &apiErr) && apiErr.StatusCode >= 500 {
+		return exitCodeServerError
+	}
+	authErr := &auth.AuthError{}
+	if errors.As(err, &authErr) && authErr.StatusCode >= 500 {
+		return exitCodeServerError
+	}
+	return exitCodeError
+}
+
+func errorMessage(err error) string {
+	statusErr := &api.StatusError{}
+	if errors.As(err, &statusErr) {
+		return fmt.Sprintf("%s недоступно: статус «%s»", actionLabels[statusErr.Action], statusErr.Status.LabelRu())
+	}
+	objectErr := &objectError{}
+	if errors.As(err, &objectErr) {
+		return fmt.Sprintf("объект %d: %s", objectErr.Index, errorMessage(objectErr.Err))
+	}
+	validationErr := &api.ValidationError{}
+	if errors.As(err, &validationErr) {
+		messages := make([]string, 0, len(validationErr.Errors))
+		for _, fieldErr := range validationErr.Errors {
+			messages = append(messages, fieldErrorMessage(fieldErr))
+		}
+		return fmt.Sprintf("временная затрата не прошла проверку: %s", strings.Join(messages, "; "))
+	}
+	authErr := &auth.AuthError{}
+	if errors.As(err, &authErr) {
+		if errors.Is(err, api.ErrUnauthorized) {
+			return "неверный логин или пароль"
+		}
+		return fmt.Sprintf("сервер СУФТ вернул ошибку %d при аутентификации", authErr.StatusCode)
+	}
+	periodErr := &api.PeriodNotFoundError{}
+	if errors.As(err, &periodErr) {
+		return fmt.Sprintf("нет периода, в который попадает дата %s", periodErr.Date.Format(dateLayout))
+	}
+	scheduleErr := &api.ScheduleNotFoundError{}
+	if errors.As(err, &scheduleErr) {
+		return fmt.Sprintf("нет расписания на период %d", scheduleErr.PeriodId)
+	}
+	var message string
+	switch {
+	case errors.Is(err, api.ErrUnauthorized):
+		message = "сессия истекла или не пройдена аутентификация, выполните команду login"
+	case errors.Is(err, api.ErrForbidden):
+		message = "недостаточно прав для выполнения операции"
+	case errors.Is(err, api.ErrNotFound):
+		message = "объект не найден, проверьте переданные id"
+	case errors.Is(err, api.ErrConflict):
+		message = "операция недопустима в текущем статусе объекта"
+	}
+	apiErr := &api.APIError{}
+	if !errors.As(err, &apiErr) {
+		return err.Error()
+	}
+	if message == "" {
+		message = fmt.Sprintf("сервер СУФТ вернул ошибку %d", apiErr.StatusCode)
+	}
+	if serverMessage := apiErr.Message(); serverMessage != "" {
+		message = fmt.Sprintf("%s (%s)", message, serverMessage)
+	}
+	return message
+}
+
+// ошибка объекта из нескольких, переданных в команду; Index начинается с 1
+type objectError struct {
+	Index int
+	Err   error
+}
+
+func (e *objectError) Error() string {
+	return fmt.Sprintf("объект %d: %v", e.Index, e.Err)
+}
+
+func (e *objectError) Unwrap() error {
+	return e.Err
+}
+
+// fieldErrorMessage возвращает описание ошибки проверки поля на русском
+func fieldErrorMessage(fieldErr api.FieldError) string {
+	var message string
+	switch fieldErr.Code {
+	case api.CodeNegativeHours:
+		message = "часы не могут быть отрицательными"
+	case api.CodeMaxDayHours:
+		message = fmt.Sprintf("не больше %g ч. за день", fieldErr.Limit)
+	case api.CodeHourStep:
+		message = fmt.Sprintf("часы должны быть кратны %g", fieldErr.Limit)
+	case api.CodeMaxWeekHours:
+		message = fmt.Sprintf("за неделю %g ч., допустимо не больше %g", fieldErr.Value, fieldErr.Limit)
+	case api.CodeNoHours:
+		message = "укажите часы хотя бы за один день"
+	case api.CodeRequired:
+		message = "обязательное поле"
+	default:
+		message = fieldErr.Message()
+	}
+	return fmt.Sprintf("%s: %s", fieldErr.Field, message)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"suftsdk/pkg/api"
+	"suftsdk/pkg/exporter"
+
+	"github.com/urfave/cli"
+)
+
+func export(c *cli.Context) error {
+	format := exporter.Format(exportFormat)
+	if !format.Valid() {
+		return fmt.Errorf("неизвестный формат выгрузки %q, доступны csv, xlsx, json и markdown", exportFormat)
+	}
+	if format == exporter.XLSX && outPath == "" {
+		return errors.New("для выгрузки в xlsx укажите файл флагом --out")
+	}
+	options := exporter.Options{CreatorApprover: api.Role(role)}
+	var err error
+	options.From, options.To, err = dateRange()
+	if err != nil {
+		return err
+	}
+	for _, id := range c.IntSlice("schedules") {
+		options.ScheduleIds = append(options.ScheduleIds, api.ScheduleId(id))
+	}
+	client, err := clientConstructor.NewClient()
+	if err != nil {
+		return err
+	}
+	rows, err := exporter.Collect(client, &options)
+	if err != nil {
+		return err
+	}
+	if outPath == "" {
+		return exporter.Write(os.Stdout, format, rows)
+	}
+	file, err := os.Create(outPath)
+	if err != nil {
+		return err
+	}
+	if err = exporter.Write(file, format, rows); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
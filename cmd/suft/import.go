@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"suftsdk/pkg/api"
+	"suftsdk/pkg/importer"
+	"unicode/utf8"
+
+	"github.com/urfave/cli"
+)
+
+func importLoggingTimes(c *cli.Context) error {
+	path := c.Args().First()
+	if path == "" {
+		return errors.New("не передан файл для импорта")
+	}
+	options, err := importOptions()
+	if err != nil {
+		return err
+	}
+	client, err := clientConstructor.NewClient()
+	if err != nil {
+		return err
+	}
+	scheduleId := api.ScheduleId(scheduleId)
+	schedule, err := client.DetailSchedule(scheduleId)
+	if err != nil {
+		return err
+	}
+	options.Period = &schedule.Period
+	rows, err := importer.ReadFile(path, options)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		return printImportRows(rows)
+	}
+	err = schedule.StatusCode.Check(api.ActionEdit)
+	if err != nil {
+		return err
+	}
+	results, err := importer.Submit(client, scheduleId, rows)
+	if err != nil {
+		return err
+	}
+	err = printImportResults(results)
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("не удалось импортировать строк: %d из %d", failed, len(results))
+	}
+	return nil
+}
+
+func importOptions() (*importer.Options, error) {
+	options := &importer.Options{Format: importer.Format(importFormat), Hours: &hoursParser}
+	if delimiter != "" {
+		comma, size := utf8.DecodeRuneInString(delimiter)
+		if size != len(delimiter) {
+			return nil, fmt.Errorf("разделитель --delimiter должен быть одним символом: %q", delimiter)
+		}
+		options.Comma = comma
+	}
+	if mappingPath != "" {
+		data, err := ioutil.ReadFile(mappingPath)
+		if err != nil {
+			return nil, err
+		}
+		// поля, которых нет в файле, остаются как в DefaultMapping
+		mapping := importer.DefaultMapping()
+		err = json.Unmarshal(data, &mapping)
+		if err != nil {
+			return nil, fmt.Errorf("неверный файл соответствия полей %s: %w", mappingPath, err)
+		}
+		options.Mapping = &mapping
+	}
+	return options, nil
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+	"suftsdk/internal/clifuncs"
+	"suftsdk/internal/output"
+	"suftsdk/pkg/api"
+	"suftsdk/pkg/importer"
+	"time"
+)
+
+// куда выводятся результаты команд
+var stdout io.Writer = os.Stdout
+
+// куда выводятся сообщения о ходе выполнения, чтобы они не смешивались с результатом
+var stderr io.Writer = os.Stderr
+
+var printer = &output.Printer{Format: output.Table, Out: os.Stdout}
+
+var dayColumnNames = [7]string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"}
+
+var dayWeekdays = [7]time.Weekday{
+	time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday,
+}
+
+// строит Printer по глобальным флагам --output, --fields и --template
+func newPrinter() *output.Printer {
+	p := &output.Printer{
+		Format:   output.Format(outputFormat),
+		Template: outputTemplate,
+		Out:      stdout,
+	}
+	if p.Format == output.Table && outputTemplate != "" {
+		p.Format = output.Template
+	}
+	for _, field := range strings.Split(outputFields, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			p.Fields = append(p.Fields, field)
+		}
+	}
+	return p
+}
+
+func periodDates(period api.Period) string {
+	if period.StartDate.IsZero() {
+		return ""
+	}
+	return period.StartDate.String() + " - " + period.EndDate.String()
+}
+
+func employeeName(employee api.Employee) string {
+	return strings.TrimSpace(strings.Join([]string{employee.LastName, employee.FirstName, employee.MiddleName}, " "))
+}
+
+// часы по дням недели временной затраты или шаблона
+type dayHours interface {
+	Hours(weekday time.Weekday) float64
+	TotalHours() float64
+}
+
+// столбцы часов по дням недели и итога для временных затрат и шаблонов
+func hoursColumns(hours func(item interface{}) dayHours) []output.Column {
+	columns := []output.Column{}
+	for i, name := range dayColumnNames {
+		weekday := dayWeekdays[i]
+		columns = append(columns, output.Column{
+			Name:    name,
+			Title:   weekdayNames[weekday],
+			Value:   func(item interface{}) interface{} { return hours(item).Hours(weekday) },
+			Default: true,
+		})
+	}
+	return append(columns, output.Column{
+		Name:    "total",
+		Title:   "Итого",
+		Value:   func(item interface{}) interface{} { return hours(item).TotalHours() },
+		Default: true,
+	})
+}
+
+func asSchedule(item interface{}) *api.Schedule { return item.(*api.Schedule) }
+
+var scheduleColumns = []output.Column{
+	{Name: "id", Title: "ID", Default: true, Value: func(item interface{}) interface{} { return asSchedule(item).Id }},
+	{Name: "week", Title: "Неделя", Default: true, Value: func(item interface{}) interface{} { return asSchedule(item).Period.WeekNumber }},
+	{Name: "dates", Title: "Даты", Default: true, Value: func(item interface{}) interface{} { return periodDates(asSchedule(item).Period) }},
+	{Name: "status", Title: "Статус", Default: true, Value: func(item interface{}) interface{} { return asSchedule(item).StatusCode.LabelRu() }},
+	{Name: "statusCode", Title: "Код статуса", Value: func(item interface{}) interface{} { return string(asSchedule(item).StatusCode) }},
+	{Name: "periodId", Title: "ID периода", Value: func(item interface{}) interface{} { return asSchedule(item).Period.Id }},
+	{Name: "author", Title: "Автор", Default: true, Value: func(item interface{}) interface{} { return employeeName(asSchedule(item).Author) }},
+	{Name: "email", Title: "Email", Value: func(item interface{}) interface{} { return asSchedule(item).Author.Email }},
+}
+
+func asLoggingTime(item interface{}) *api.LoggingTime { return item.(*api.LoggingTime) }
+
+var loggingTimeColumns = append(append([]output.Column{
+	{Name: "id", Title: "ID", Default: true, Value: func(item interface{}) interface{} { return asLoggingTime(item).Id }},
+	{Name: "project", Title: "Проект", Default: true, Value: func(item interface{}) interface{} { return asLoggingTime(item).ProjectId }},
+	{Name: "workKind", Title: "Вид работ", Default: true, Value: func(item interface{}) interface{} { return asLoggingTime(item).WorkKindId }},
+	{Name: "task", Title: "Задача", Default: true, Value: func(item interface{}) interface{} { return asLoggingTime(item).Task }},
+	{Name: "status", Title: "Статус", Default: true, Value: func(item interface{}) interface{} { return asLoggingTime(item).StatusCode.LabelRu() }},
+	{Name: "statusCode", Title: "Код статуса", Value: func(item interface{}) interface{} { return string(asLoggingTime(item).StatusCode) }},
+}, hoursColumns(func(item interface{}) dayHours {
+	return asLoggingTime(item)
+})...),
+	output.Column{Name: "comment", Title: "Комментарий", Value: func(item interface{}) interface{} { return asLoggingTime(item).CommentEmployee }},
+	output.Column{Name: "adminComment", Title: "Комментарий согласующего", Value: func(item interface{}) interface{} { return asLoggingTime(item).CommentAdminEmployee }},
+	output.Column{Name: "approver", Title: "Согласующий", Value: func(item interface{}) interface{} { return employeeName(asLoggingTime(item).AdminEmployee) }},
+)
+
+func asPeriod(item interface{}) *api.Period { return item.(*api.Period) }
+
+var periodColumns = []output.Column{
+	{Name: "id", Title: "ID", Default: true, Value: func(item interface{}) interface{} { return asPeriod(item).Id }},
+	{Name: "week", Title: "Неделя", Default: true, Value: func(item interface{}) interface{} { return asPeriod(item).WeekNumber }},
+	{Name: "dates", Title: "Даты", Value: func(item interface{}) interface{} { return periodDates(*asPeriod(item)) }},
+	{Name: "start", Title: "Начало", Default: true, Value: func(item interface{}) interface{} { return asPeriod(item).StartDate }},
+	{Name: "end", Title: "Конец", Default: true, Value: func(item interface{}) interface{} { return asPeriod(item).EndDate }},
+	{Name: "close", Title: "Закрытие", Default: true, Value: func(item interface{}) interface{} { return asPeriod(item).CloseDate }},
+}
+
+func asProject(item interface{}) *api.Project { return item.(*api.Project) }
+
+var projectColumns = []output.Column{
+	{Name: "id", Title: "ID", Default: true, Value: func(item interface{}) interface{} { return asProject(item).Id }},
+	{Name: "code", Title: "Код", Default: true, Value: func(item interface{}) interface{} { return asProject(item).Code }},
+	{Name: "name", Title: "Название", Default: true, Value: func(item interface{}) interface{} { return asProject(item).Name }},
+}
+
+func asWorkKind(item interface{}) *api.WorkKind { return item.(*api.WorkKind) }
+
+var workKindColumns = []output.Column{
+	{Name: "id", Title: "ID", Default: true, Value: func(item interface{}) interface{} { return asWorkKind(item).Id }},
+	{Name: "code", Title: "Код", Default: true, Value: func(item interface{}) interface{} { return asWorkKind(item).Code }},
+	{Name: "name", Title: "Название", Default: true, Value: func(item interface{}) interface{} { return asWorkKind(item).Name }},
+}
+
+func asTemplate(item interface{}) *clifuncs.Template { return item.(*clifuncs.Template) }
+
+var templateColumns = append(append([]output.Column{
+	{Name: "name", Title: "Шаблон", Default: true, Value: func(item interface{}) interface{} { return asTemplate(item).Name }},
+	{Name: "project", Title: "Проект", Default: true, Value: func(item interface{}) interface{} { return asTemplate(item).ProjectId }},
+	{Name: "workKind", Title: "Вид работ", Default: true, Value: func(item interface{}) interface{} { return asTemplate(item).WorkKindId }},
+	{Name: "task", Title: "Задача", Default: true, Value: func(item interface{}) interface{} { return asTemplate(item).Task }},
+}, hoursColumns(func(item interface{}) dayHours {
+	return &asTemplate(item).AddLoggingTime
+})...),
+	output.Column{Name: "comment", Title: "Комментарий", Value: func(item interface{}) interface{} { return asTemplate(item).CommentEmployee }},
+)
+
+// строка результата copy-week
+type copyWeekLine struct {
+	ScheduleId    int    `json:"scheduleId"`
+	Task          string `json:"task"`
+	Result        string `json:"result"`
+	LoggingTimeId int    `json:"loggingTimeId,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+func asCopyWeekLine(item interface{}) *copyWeekLine { return item.(*copyWeekLine) }
+
+var copyWeekLineColumns = []output.Column{
+	{Name: "scheduleId", Title: "Расписание", Default: true, Value: func(item interface{}) interface{} { return asCopyWeekLine(item).ScheduleId }},
+	{Name: "task", Title: "Задача", Default: true, Value: func(item interface{}) interface{} { return asCopyWeekLine(item).Task }},
+	{Name: "result", Title: "Результат", Default: true, Value: func(item interface{}) interface{} { return asCopyWeekLine(item).Result }},
+	{Name: "loggingTimeId", Title: "ID", Default: true, Value: func(item interface{}) interface{} { return asCopyWeekLine(item).LoggingTimeId }},
+	{Name: "error", Title: "Ошибка", Default: true, Value: func(item interface{}) interface{} { return asCopyWeekLine(item).Error }},
+}
+
+// строка таблицы импорта
+type importLine struct {
+	Line        int                 `json:"line"`
+	LoggingTime *api.AddLoggingTime `json:"loggingTime"`
+	Error       string              `json:"error,omitempty"`
+}
+
+func asImportLine(item interface{}) *importLine { return item.(*importLine) }
+
+var importLineColumns = append(append([]output.Column{
+	{Name: "line", Title: "Строка", Default: true, Value: func(item interface{}) interface{} { return asImportLine(item).Line }},
+	{Name: "project", Title: "Проект", Default: true, Value: func(item interface{}) interface{} { return asImportLine(item).LoggingTime.ProjectId }},
+	{Name: "workKind", Title: "Вид работ", Default: true, Value: func(item interface{}) interface{} { return asImportLine(item).LoggingTime.WorkKindId }},
+	{Name: "task", Title: "Задача", Default: true, Value: func(item interface{}) interface{} { return asImportLine(item).LoggingTime.Task }},
+}, hoursColumns(func(item interface{}) dayHours {
+	return asImportLine(item).LoggingTime
+})...),
+	output.Column{Name: "error", Title: "Ошибка", Default: true, Value: func(item interface{}) interface{} { return asImportLine(item).Error }},
+)
+
+// результат импорта строки
+type importResult struct {
+	Line          int    `json:"line"`
+	Result        string `json:"result"`
+	LoggingTimeId int    `json:"loggingTimeId,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+func asImportResult(item interface{}) *importResult { return item.(*importResult) }
+
+var importResultColumns = []output.Column{
+	{Name: "line", Title: "Строка", Default: true, Value: func(item interface{}) interface{} { return asImportResult(item).Line }},
+	{Name: "result", Title: "Результат", Default: true, Value: func(item interface{}) interface{} { return asImportResult(item).Result }},
+	{Name: "loggingTimeId", Title: "ID", Default: true, Value: func(item interface{}) interface{} { return asImportResult(item).LoggingTimeId }},
+	{Name: "error", Title: "Ошибка", Default: true, Value: func(item interface{}) interface{} { return asImportResult(item).Error }},
+}
+
+func printSchedules(schedules []*api.Schedule) error {
+	items := make([]interface{}, len(schedules))
+	for i, schedule := range schedules {
+		items[i] = schedule
+	}
+	return printer.Print(items, scheduleColumns)
+}
+
+func printLoggingTimes(loggingTimes []*api.LoggingTime) error {
+	items := make([]interface{}, len(loggingTimes))
+	for i, loggingTime := range loggingTimes {
+		items[i] = loggingTime
+	}
+	return printer.Print(items, loggingTimeColumns)
+}
+
+func printPeriods(periods []*api.Period) error {
+	items := make([]interface{}, len(periods))
+	for i, period := range periods {
+		items[i] = period
+	}
+	return printer.Print(items, periodColumns)
+}
+
+func printProjects(projects []*api.Project) error {
+	items := make([]interface{}, len(projects))
+	for i, project := range projects {
+		items[i] = project
+	}
+	return printer.Print(items, projectColumns)
+}
+
+func printWorkKinds(workKinds []*api.WorkKind) error {
+	items := make([]interface{}, len(workKinds))
+	for i, workKind := range workKinds {
+		items[i] = workKind
+	}
+	return printer.Print(items, workKindColumns)
+}
+
+func printCopyWeekResult(result *api.CopyWeekResult) error {
+	items := make([]interface{}, len(result.Lines))
+	for i, line := range result.Lines {
+		item := &copyWeekLine{ScheduleId: result.Schedule.Id, Task: line.Source.Task}
+		switch {
+		case line.Skipped:
+			item.Result = "пропущено, уже есть в расписании"
+		case line.Err != nil:
+			item.Result = "ошибка"
+			item.Error = errorMessage(line.Err)
+		default:
+			item.Result = "создано"
+			item.LoggingTimeId = line.Created.Id
+		}
+		items[i] = item
+	}
+	return printer.Print(items, copyWeekLineColumns)
+}
+
+func printImportRows(rows []importer.Row) error {
+	items := make([]interface{}, len(rows))
+	for i, row := range rows {
+		item := &importLine{Line: row.Line, LoggingTime: row.LoggingTime}
+		if row.Err != nil {
+			item.Error = errorMessage(row.Err)
+		}
+		items[i] = item
+	}
+	return printer.Print(items, importLineColumns)
+}
+
+func printImportResults(results []importer.Result) error {
+	items := make([]interface{}, len(results))
+	for i, result := range results {
+		item := &importResult{Line: result.Row.Line}
+		if result.Err != nil {
+			item.Result = "ошибка"
+			item.Error = errorMessage(result.Err)
+		} else {
+			item.Result = "добавлена"
+			item.LoggingTimeId = result.Created.Id
+		}
+		items[i] = item
+	}
+	return printer.Print(items, importResultColumns)
+}
+
+func printTemplates(templates []*clifuncs.Template) error {
+	items := make([]interface{}, len(templates))
+	for i, template := range templates {
+		items[i] = template
+	}
+	return printer.Print(items, templateColumns)
+}
@@ -1,19 +1,32 @@
 package main
 
 import (
-	"encoding/json"
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"log"
+	"net/http"
 	"os"
 	"os/exec"
+	"strings"
+	"suftsdk/internal/auth"
 	"suftsdk/internal/clifuncs"
+	"suftsdk/internal/output"
 	"suftsdk/pkg/api"
+	"suftsdk/pkg/cassette"
+	"suftsdk/pkg/exporter"
+	"suftsdk/pkg/logging"
+	"time"
 
 	"github.com/urfave/cli"
 )
 
 const scheduleCategory string = "Расписания"
 const loggingTimeCategory string = "Временные затраты"
+const referenceCategory string = "Справочники"
+const dateLayout string = "2006-01-02"
 
 var scheduleId int
 var loggingTimeId int
@@ -23,6 +36,39 @@ var size int
 var role string
 var editor string
 var adminComment string
+var allPages bool
+var search string
+var dateFrom string
+var dateTo string
+var date string
+var ensure bool
+var summary bool
+var norm float64
+var fromScheduleId int
+var toPeriodId int
+var keepHours bool
+var zeroHours bool
+var dryRun bool
+var importFormat string
+var delimiter string
+var mappingPath string
+var exportFormat string
+var outPath string
+var outputFormat string
+var outputFields string
+var outputTemplate string
+var projectId int
+var workKindId int
+var task string
+var employeeComment string
+var weekHours [7]string
+var hoursList string
+var roundHours float64
+var rounding string
+var cassettePath string
+var logLevel string
+var dumpHTTP bool
+var readStdin bool
 
 var scheduleIdFlag cli.Flag = cli.IntFlag{
 	Name:        "schedule-id, scid",
@@ -43,6 +89,223 @@ var sizeFlag cli.Flag = cli.IntFlag{
 	Destination: &size,
 }
 
+var allFlag cli.Flag = cli.BoolFlag{
+	Name:        "all, a",
+	Usage:       "Вывести элементы со всех страниц, начиная с указанной",
+	Destination: &allPages,
+}
+
+var searchFlag cli.Flag = cli.StringFlag{
+	Name:        "search, q",
+	Usage:       "Подстрока кода или названия",
+	Destination: &search,
+}
+
+var fromFlag cli.Flag = cli.StringFlag{
+	Name:        "from",
+	Usage:       "Начальная дата в формате ГГГГ-ММ-ДД",
+	Destination: &dateFrom,
+}
+
+var toFlag cli.Flag = cli.StringFlag{
+	Name:        "to",
+	Usage:       "Конечная дата в формате ГГГГ-ММ-ДД",
+	Destination: &dateTo,
+}
+
+var dateFlag cli.Flag = cli.StringFlag{
+	Name:        "date, d",
+	Usage:       "Дата в формате ГГГГ-ММ-ДД, по умолчанию сегодня",
+	Destination: &date,
+}
+
+var ensureFlag cli.Flag = cli.BoolFlag{
+	Name:        "ensure",
+	Usage:       "Создать расписание, если его нет",
+	Destination: &ensure,
+}
+
+var summaryFlag cli.Flag = cli.BoolFlag{
+	Name:        "summary",
+	Usage:       "Вывести сводку по часам расписания",
+	Destination: &summary,
+}
+
+var normFlag cli.Flag = cli.Float64Flag{
+	Name:        "norm",
+	Usage:       "Норма часов за неделю для сводки",
+	Value:       api.DefaultWeeklyNorm,
+	Destination: &norm,
+}
+
+var fromScheduleFlag cli.Flag = cli.IntFlag{
+	Name:        "from-schedule",
+	Usage:       "id расписания, из которого копируются временные затраты",
+	Required:    true,
+	Destination: &fromScheduleId,
+}
+
+var toPeriodFlag cli.Flag = cli.IntFlag{
+	Name:        "to-period",
+	Usage:       "id периода, в расписание на который копируются временные затраты",
+	Required:    true,
+	Destination: &toPeriodId,
+}
+
+var keepHoursFlag cli.Flag = cli.BoolFlag{
+	Name:        "keep-hours",
+	Usage:       "Перенести часы (по умолчанию)",
+	Destination: &keepHours,
+}
+
+var zeroHoursFlag cli.Flag = cli.BoolFlag{
+	Name:        "zero-hours",
+	Usage:       "Создать строки с нулевыми часами",
+	Destination: &zeroHours,
+}
+
+var dryRunFlag cli.Flag = cli.BoolFlag{
+	Name:        "dry-run",
+	Usage:       "Только показать прочитанные строки, не добавляя их",
+	Destination: &dryRun,
+}
+
+var importFormatFlag cli.Flag = cli.StringFlag{
+	Name:        "format, f",
+	Usage:       "Формат файла (csv или xlsx), по умолчанию по расширению",
+	Destination: &importFormat,
+}
+
+var delimiterFlag cli.Flag = cli.StringFlag{
+	Name:        "delimiter",
+	Usage:       "Разделитель столбцов CSV",
+	Value:       ",",
+	Destination: &delimiter,
+}
+
+var mappingFlag cli.Flag = cli.StringFlag{
+	Name:        "mapping",
+	Usage:       "JSON-файл с соответствием полей заголовкам столбцов",
+	Destination: &mappingPath,
+}
+
+var schedulesFlag cli.Flag = cli.IntSliceFlag{
+	Name:  "schedules",
+	Usage: "id расписаний, можно указать несколько раз; если не указаны, расписания выбираются по датам",
+}
+
+var exportFormatFlag cli.Flag = cli.StringFlag{
+	Name:        "format, f",
+	Usage:       "Формат выгрузки: csv, xlsx, json или markdown",
+	Value:       string(exporter.CSV),
+	Destination: &exportFormat,
+}
+
+var outFlag cli.Flag = cli.StringFlag{
+	Name:        "out, o",
+	Usage:       "Файл для выгрузки, по умолчанию стандартный вывод",
+	Destination: &outPath,
+}
+
+var outputFlag cli.Flag = cli.StringFlag{
+	Name:        "output",
+	Usage:       "Формат вывода: table, json, yaml, ndjson или template",
+	Value:       string(output.Table),
+	Destination: &outputFormat,
+}
+
+var fieldsFlag cli.Flag = cli.StringFlag{
+	Name:        "fields",
+	Usage:       "Выводимые столбцы через запятую, например id,status,total",
+	Destination: &outputFields,
+}
+
+var templateFlag cli.Flag = cli.StringFlag{
+	Name:        "template",
+	Usage:       "Шаблон text/template для вывода каждого элемента, например '{{.Id}} {{.Task}}'",
+	Destination: &outputTemplate,
+}
+
+var projectFlag cli.Flag = cli.IntFlag{
+	Name:        "project",
+	Usage:       "id проекта",
+	Destination: &projectId,
+}
+
+var workKindFlag cli.Flag = cli.IntFlag{
+	Name:        "work-kind",
+	Usage:       "id вида работ",
+	Destination: &workKindId,
+}
+
+var taskFlag cli.Flag = cli.StringFlag{
+	Name:        "task",
+	Usage:       "Задача",
+	Destination: &task,
+}
+
+var employeeCommentFlag cli.Flag = cli.StringFlag{
+	Name:        "comment",
+	Usage:       "Комментарий сотрудника",
+	Destination: &employeeComment,
+}
+
+var hoursFlag cli.Flag = cli.StringFlag{
+	Name:        "hours",
+	Usage:       "Часы с понедельника по воскресенье через запятую, например 8,8,8,8,7:30,0,0",
+	Destination: &hoursList,
+}
+
+var stdinFlag cli.Flag = cli.BoolFlag{
+	Name:        "stdin",
+	Usage:       "Прочитать временные затраты в формате JSON или NDJSON из стандартного ввода",
+	Destination: &readStdin,
+}
+
+// флаги часов по дням недели --mon .. --sun
+var dayFlags = func() []cli.Flag {
+	flags := make([]cli.Flag, len(dayColumnNames))
+	for i, name := range dayColumnNames {
+		flags[i] = cli.StringFlag{
+			Name:        name,
+			Usage:       fmt.Sprintf("Часы за %s", strings.ToLower(weekdayNames[dayWeekdays[i]])),
+			Destination: &weekHours[i],
+		}
+	}
+	return flags
+}()
+
+var roundHoursFlag cli.Flag = cli.Float64Flag{
+	Name:        "round-hours",
+	Usage:       "Шаг округления введённых часов, например 0.25; 0 - без округления",
+	Destination: &roundHours,
+}
+
+var roundingFlag cli.Flag = cli.StringFlag{
+	Name:        "rounding",
+	Usage:       "Способ округления часов: nearest, up или down",
+	Value:       api.RoundNearest.String(),
+	Destination: &rounding,
+}
+
+var recordCassetteFlag cli.Flag = cli.StringFlag{
+	Name:        "record-cassette",
+	Usage:       "Записать запросы к СУФТ и ответы в файл для отчёта об ошибке (токены и персональные данные скрываются)",
+	Destination: &cassettePath,
+}
+
+var logLevelFlag cli.Flag = cli.StringFlag{
+	Name:        "log-level",
+	Usage:       "Писать журнал запросов к СУФТ в stderr, начиная с уровня: debug, info, warn или error",
+	Destination: &logLevel,
+}
+
+var dumpHTTPFlag cli.Flag = cli.BoolFlag{
+	Name:        "dump-http",
+	Usage:       "Печатать запросы к СУФТ и ответы в stderr (токены скрываются)",
+	Destination: &dumpHTTP,
+}
+
 var roleFlag cli.Flag = cli.StringFlag{
 	Name:        "role, r",
 	Usage:       "Роль клиента (approver или creator)",
@@ -79,6 +342,15 @@ var commentFlag cli.Flag = cli.StringFlag{
 
 var clientConstructor clifuncs.ClientBuilder
 
+// разбор часов во флагах, файлах и импорте, настраивается флагами --round-hours и --rounding
+var hoursParser api.HoursParser
+
+// записывает запросы к СУФТ, если задан флаг --record-cassette
+var recorder *cassette.Recorder
+
+// откуда читаются временные затраты с флагом --stdin
+var stdin io.Reader = os.Stdin
+
 func main() {
 	clientConstructor = &clifuncs.ClientInit{}
 	app, err := cliFunc()
@@ -87,7 +359,8 @@ func main() {
 	}
 	err = app.Run(os.Args)
 	if err != nil {
-		log.Fatalln(err)
+		fmt.Fprintln(os.Stderr, errorMessage(err))
+		os.Exit(exitCode(err))
 	}
 
 }
@@ -96,6 +369,38 @@ func cliFunc() (app *cli.App, err error) {
 	app = cli.NewApp()
 	app.Name = "SUFT CLI"
 	app.Usage = "CLI предоставляет возможность взаимодействия с api СУФТ (системы учета фактических трудозатрат)"
+	app.Flags = []cli.Flag{
+		outputFlag,
+		fieldsFlag,
+		templateFlag,
+		roundHoursFlag,
+		roundingFlag,
+		recordCassetteFlag,
+		logLevelFlag,
+		dumpHTTPFlag,
+	}
+	app.Before = func(c *cli.Context) error {
+		printer = newPrinter()
+		err := printer.Validate()
+		if err != nil {
+			return err
+		}
+		recorder = nil
+		if cassettePath != "" || logLevel != "" || dumpHTTP {
+			clientConstructor, err = newClientInit()
+			if err != nil {
+				return err
+			}
+		}
+		hoursParser, err = newHoursParser()
+		return err
+	}
+	app.After = func(c *cli.Context) error {
+		if recorder == nil {
+			return nil
+		}
+		return recorder.Save()
+	}
 	app.Commands = []cli.Command{
 		{
 			Name:     "login",
@@ -118,6 +423,7 @@ func cliFunc() (app *cli.App, err error) {
 				pageFlag,
 				sizeFlag,
 				roleFlag,
+				allFlag,
 			},
 			Action: schedules,
 		},
@@ -128,6 +434,8 @@ func cliFunc() (app *cli.App, err error) {
 			Category: scheduleCategory,
 			Flags: []cli.Flag{
 				scheduleIdFlag,
+				summaryFlag,
+				normFlag,
 			},
 			Action: scheduleDetail,
 		},
@@ -142,6 +450,48 @@ func cliFunc() (app *cli.App, err error) {
 			},
 			Action: addSchedule,
 		},
+		{
+			Name:        "week",
+			Usage:       "Период и расписание на неделю",
+			Description: "Выводит период и расписание на неделю, в которую попадает дата",
+			Aliases:     []string{"w"},
+			Category:    scheduleCategory,
+			Flags: []cli.Flag{
+				dateFlag,
+				ensureFlag,
+			},
+			Action: week,
+		},
+		{
+			Name:        "export",
+			Usage:       "Выгрузка расписаний и временных затрат",
+			Description: "Выгружает временные затраты расписаний за период или по списку id в CSV, XLSX, JSON или Markdown",
+			Category:    scheduleCategory,
+			Aliases:     []string{"exp"},
+			Flags: []cli.Flag{
+				fromFlag,
+				toFlag,
+				schedulesFlag,
+				roleFlag,
+				exportFormatFlag,
+				outFlag,
+			},
+			Action: export,
+		},
+		{
+			Name:        "copy-week",
+			Usage:       "Копирование временных затрат в расписание на другую неделю",
+			Description: "Создаёт расписание на период, если его нет, и копирует в него временные затраты, пропуская уже существующие строки",
+			Aliases:     []string{"cw"},
+			Category:    scheduleCategory,
+			Flags: []cli.Flag{
+				fromScheduleFlag,
+				toPeriodFlag,
+				keepHoursFlag,
+				zeroHoursFlag,
+			},
+			Action: copyWeek,
+		},
 		{
 			Name:     "submit-for-approve",
 			Usage:    "Отправить расписание на утверждение",
@@ -162,6 +512,7 @@ func cliFunc() (app *cli.App, err error) {
 				scheduleIdFlag,
 				pageFlag,
 				sizeFlag,
+				allFlag,
 			},
 			Category: loggingTimeCategory,
 			Action:   loggingTimes,
@@ -183,11 +534,94 @@ func cliFunc() (app *cli.App, err error) {
 			Usage:    "Добавление временной затраты",
 			Category: loggingTimeCategory,
 			Aliases:  []string{"al"},
+			Description: "Временная затрата задаётся флагами, читается из стандартного ввода (--stdin) " +
+				"или, если не передано ни то, ни другое, редактируется в текстовом редакторе",
+			Flags: append([]cli.Flag{
+				scheduleIdFlag,
+				editorFlag,
+				projectFlag,
+				workKindFlag,
+				taskFlag,
+				employeeCommentFlag,
+				hoursFlag,
+				stdinFlag,
+			}, dayFlags...),
+			Action: addLoggingTime,
+		},
+		{
+			Name:        "import",
+			Usage:       "Импорт временных затрат из CSV или XLSX",
+			Description: "Читает строки таблицы и добавляет каждую в расписание, выводя результат по строкам",
+			Category:    loggingTimeCategory,
+			Aliases:     []string{"imp"},
+			ArgsUsage:   "file",
 			Flags: []cli.Flag{
 				scheduleIdFlag,
+				dryRunFlag,
+				importFormatFlag,
+				delimiterFlag,
+				mappingFlag,
+			},
+			Action: importLoggingTimes,
+		},
+		{
+			Name:        "template",
+			Usage:       "Шаблоны временных затрат",
+			Description: "Шаблоны хранятся в файле templates.json рядом с suft_config.json",
+			Category:    loggingTimeCategory,
+			Aliases:     []string{"tpl"},
+			Subcommands: []cli.Command{
+				{
+					Name:      "save",
+					Usage:     "Создание или изменение шаблона в редакторе",
+					ArgsUsage: "name",
+					Flags: []cli.Flag{
+						editorFlag,
+					},
+					Action: saveTemplate,
+				},
+				{
+					Name:   "list",
+					Usage:  "Список шаблонов",
+					Action: listTemplates,
+				},
+				{
+					Name:      "show",
+					Usage:     "Просмотр шаблона",
+					ArgsUsage: "name",
+					Action:    showTemplate,
+				},
+				{
+					Name:      "delete",
+					Usage:     "Удаление шаблона",
+					ArgsUsage: "name",
+					Action:    deleteTemplate,
+				},
+				{
+					Name:        "apply",
+					Usage:       "Добавление временной затраты по шаблону",
+					Description: "Открывает в редакторе временную затрату, заполненную по шаблону, после сохранения файла она добавляется в расписание",
+					ArgsUsage:   "name",
+					Flags: []cli.Flag{
+						scheduleIdFlag,
+						editorFlag,
+					},
+					Action: applyTemplate,
+				},
+			},
+		},
+		{
+			Name:        "edit-logging-time",
+			Usage:       "Изменение временной затраты",
+			Description: "Открывает в редакторе текущие значения временной затраты, после сохранения файла изменения отправляются в СУФТ",
+			Category:    loggingTimeCategory,
+			Aliases:     []string{"elt"},
+			Flags: []cli.Flag{
+				scheduleIdFlag,
+				loggingTimeIdFlag,
 				editorFlag,
 			},
-			Action: addLoggingTime,
+			Action: editLoggingTime,
 		},
 		{
 			Name:     "remove-logging-time",
@@ -226,6 +660,42 @@ func cliFunc() (app *cli.App, err error) {
 			Category: loggingTimeCategory,
 			Action:   declineLoggingTime,
 		},
+		{
+			Name:     "projects",
+			Usage:    "Список проектов",
+			Aliases:  []string{"prj"},
+			Category: referenceCategory,
+			Flags: []cli.Flag{
+				searchFlag,
+				pageFlag,
+				sizeFlag,
+			},
+			Action: projects,
+		},
+		{
+			Name:     "work-kinds",
+			Usage:    "Список видов работ",
+			Aliases:  []string{"wk"},
+			Category: referenceCategory,
+			Flags: []cli.Flag{
+				searchFlag,
+			},
+			Action: workKinds,
+		},
+		{
+			Name:        "periods",
+			Usage:       "Список периодов",
+			Description: "Выводит периоды, пересекающиеся с интервалом дат --from и --to",
+			Aliases:     []string{"pds"},
+			Category:    referenceCategory,
+			Flags: []cli.Flag{
+				fromFlag,
+				toFlag,
+				pageFlag,
+				sizeFlag,
+			},
+			Action: periods,
+		},
 	}
 
 	if err != nil {
@@ -235,11 +705,11 @@ func cliFunc() (app *cli.App, err error) {
 }
 
 func login(c *cli.Context) error {
-	err := clifuncs.LoginSuft()
+	err := clifuncs.LoginSuft(authOptions())
 	if err != nil {
 		return err
 	}
-	fmt.Println("Клиент успешно прошел аутентификацию")
+	fmt.Fprintln(stderr, "Клиент успешно прошел аутентификацию")
 	return nil
 }
 
@@ -248,7 +718,7 @@ func logout(c *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	fmt.Println("Успешный выход из клиента")
+	fmt.Fprintln(stderr, "Успешный выход из клиента")
 	return nil
 }
 
@@ -266,19 +736,17 @@ func schedules(c *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	schedules, err := client.Schedules(&options)
+	var schedules []*api.Schedule
+	if allPages {
+		schedules, err = api.AllSchedules(client, &options)
+	} else {
+		schedules, err = client.Schedules(&options)
+	}
 	if err != nil {
 		return err
 	}
 
-	for _, schedule := range schedules {
-		scheduleJSON, err := json.Marshal(schedule)
-		if err != nil {
-			return err
-		}
-		fmt.Printf("%s\n", scheduleJSON)
-	}
-	return nil
+	return printSchedules(schedules)
 }
 
 func scheduleDetail(c *cli.Context) error {
@@ -291,13 +759,18 @@ func scheduleDetail(c *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	scheduleJSON, err := json.Marshal(schedule)
-	if err != nil {
-		return err
+	if summary {
+		loggingTimes, err := api.AllLoggingTimes(client, schedId, nil)
+		if err != nil {
+			return err
+		}
+		scheduleSummary := api.Summarize(schedule.Period, loggingTimes, norm)
+		if printer.Format == output.Table {
+			return printSummary(stdout, schedule, scheduleSummary)
+		}
+		return printer.PrintOne(scheduleSummary, nil)
 	}
-	fmt.Printf("%s\n", scheduleJSON)
-
-	return nil
+	return printer.PrintOne(schedule, scheduleColumns)
 }
 
 func addSchedule(c *cli.Context) error {
@@ -310,12 +783,70 @@ func addSchedule(c *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	scheduleJSON, err := json.Marshal(schedule)
+	return printer.PrintOne(schedule, scheduleColumns)
+}
+
+func week(c *cli.Context) error {
+	day := time.Now()
+	if date != "" {
+		var err error
+		day, err = time.Parse(dateLayout, date)
+		if err != nil {
+			return fmt.Errorf("неверный формат даты --date %q, ожидается ГГГГ-ММ-ДД", date)
+		}
+	}
+	client, err := clientConstructor.NewClient()
 	if err != nil {
 		return err
 	}
-	fmt.Printf("%s\n", scheduleJSON)
+	period, err := api.PeriodForDate(client, day)
+	if err != nil {
+		return err
+	}
+	err = printer.PrintOne(period, periodColumns)
+	if err != nil {
+		return err
+	}
+	if printer.Format == output.Table {
+		fmt.Fprintln(stdout)
+	}
 
+	var schedule *api.Schedule
+	if ensure {
+		schedule, err = api.EnsureScheduleForPeriod(client, api.PeriodId(period.Id))
+	} else {
+		schedule, err = api.ScheduleForPeriod(client, api.PeriodId(period.Id))
+	}
+	if errors.Is(err, api.ErrNotFound) {
+		fmt.Fprintln(stderr, "Расписание на эту неделю ещё не создано, для создания выполните команду с флагом --ensure")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return printer.PrintOne(schedule, scheduleColumns)
+}
+
+func copyWeek(c *cli.Context) error {
+	if keepHours && zeroHours {
+		return errors.New("флаги --keep-hours и --zero-hours нельзя указывать вместе")
+	}
+	client, err := clientConstructor.NewClient()
+	if err != nil {
+		return err
+	}
+	options := api.OptionsCW{ZeroHours: zeroHours}
+	result, err := api.CopyWeek(client, api.ScheduleId(fromScheduleId), api.PeriodId(toPeriodId), &options)
+	if err != nil {
+		return err
+	}
+	err = printCopyWeekResult(result)
+	if err != nil {
+		return err
+	}
+	if failed := result.Failed(); failed > 0 {
+		return fmt.Errorf("не удалось скопировать строк: %d из %d", failed, len(result.Lines))
+	}
 	return nil
 }
 
@@ -325,17 +856,15 @@ func submitForApprove(c *cli.Context) error {
 		return err
 	}
 	schedId := api.ScheduleId(scheduleId)
-
-	schedule, err := client.SubmitForApproveSchedule(schedId)
+	err = checkScheduleStatus(client, schedId, api.ActionSubmit)
 	if err != nil {
 		return err
 	}
-	scheduleJSON, err := json.Marshal(schedule)
+	schedule, err := client.SubmitForApproveSchedule(schedId)
 	if err != nil {
 		return err
 	}
-	fmt.Printf("%s\n", scheduleJSON)
-	return nil
+	return printer.PrintOne(schedule, scheduleColumns)
 }
 
 func loggingTimes(c *cli.Context) error {
@@ -349,18 +878,16 @@ func loggingTimes(c *cli.Context) error {
 	}
 	options.Page = page
 	scheduleId := api.ScheduleId(scheduleId)
-	loggingTimeList, err := client.LoggingTimeList(scheduleId, &options)
+	var loggingTimeList []*api.LoggingTime
+	if allPages {
+		loggingTimeList, err = api.AllLoggingTimes(client, scheduleId, &options)
+	} else {
+		loggingTimeList, err = client.LoggingTimeList(scheduleId, &options)
+	}
 	if err != nil {
 		return err
 	}
-	for _, schedule := range loggingTimeList {
-		loggingTimeJSON, err := json.Marshal(schedule)
-		if err != nil {
-			return err
-		}
-		fmt.Printf("%s\n\n", loggingTimeJSON)
-	}
-	return nil
+	return printLoggingTimes(loggingTimeList)
 }
 
 func loggingTimeDetail(c *cli.Context) error {
@@ -374,45 +901,233 @@ func loggingTimeDetail(c *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	loggingTimeJSON, err := json.Marshal(loggingTime)
+	return printer.PrintOne(loggingTime, loggingTimeColumns)
+}
+
+func addLoggingTime(c *cli.Context) error {
+	loggingTimes, err := loggingTimesFromArgs(c)
 	if err != nil {
 		return err
 	}
-	fmt.Printf("%s\n\n", loggingTimeJSON)
-	return nil
+	for i, loggingTime := range loggingTimes {
+		err = loggingTime.ValidateWith(validationRules())
+		if err == nil {
+			continue
+		}
+		if len(loggingTimes) > 1 {
+			return &objectError{Index: i + 1, Err: err}
+		}
+		return err
+	}
+	client, err := clientConstructor.NewClient()
+	if err != nil {
+		return err
+	}
+	scheduleId := api.ScheduleId(scheduleId)
+	err = checkScheduleStatus(client, scheduleId, api.ActionEdit)
+	if err != nil {
+		return err
+	}
+	if loggingTimes == nil {
+		path, err := clifuncs.GenLoggingTimeFile()
+		if err != nil {
+			return err
+		}
+		return editAndAddLoggingTime(client, scheduleId, path)
+	}
+	created := make([]*api.LoggingTime, 0, len(loggingTimes))
+	for i, loggingTime := range loggingTimes {
+		loggingTimeResp, err := client.AddLoggingTime(scheduleId, loggingTime)
+		if err != nil {
+			printErr := printLoggingTimes(created)
+			if printErr != nil {
+				return printErr
+			}
+			return &objectError{Index: i + 1, Err: err}
+		}
+		created = append(created, loggingTimeResp)
+	}
+	return printLoggingTimes(created)
 }
 
-func addLoggingTime(c *cli.Context) error {
-	client, err := clientConstructor.NewClient()
+// временные затраты из флагов или стандартного ввода; nil - не передано ни то,
+// ни другое, и временная затрата редактируется в редакторе
+func loggingTimesFromArgs(c *cli.Context) ([]*api.AddLoggingTime, error) {
+	fieldsSet := false
+	for _, name := range append([]string{"project", "work-kind", "task", "comment", "hours"}, dayColumnNames[:]...) {
+		if c.IsSet(name) {
+			fieldsSet = true
+		}
+	}
+	if readStdin {
+		if fieldsSet {
+			return nil, errors.New("флаг --stdin нельзя указывать вместе с полями временной затраты")
+		}
+		return clifuncs.LoggingTimesFromReader(stdin, hoursParser)
+	}
+	if !fieldsSet {
+		return nil, nil
+	}
+	loggingTime := &api.AddLoggingTime{
+		ProjectId:       projectId,
+		WorkKindId:      workKindId,
+		Task:            task,
+		CommentEmployee: employeeComment,
+	}
+	if c.IsSet("hours") {
+		for _, name := range dayColumnNames {
+			if c.IsSet(name) {
+				return nil, fmt.Errorf("флаг --hours нельзя указывать вместе с --%s", name)
+			}
+		}
+		values := strings.Split(hoursList, ",")
+		if len(values) != len(dayColumnNames) {
+			return nil, fmt.Errorf("во флаге --hours нужно %d значений через запятую, передано %d", len(dayColumnNames), len(values))
+		}
+		for i, value := range values {
+			hours, err := hoursParser.Parse(value)
+			if err != nil {
+				return nil, fmt.Errorf("флаг --hours: %w", err)
+			}
+			loggingTime.SetHours(dayWeekdays[i], hours)
+		}
+	} else {
+		for i, value := range weekHours {
+			hours, err := hoursParser.Parse(value)
+			if err != nil {
+				return nil, fmt.Errorf("флаг --%s: %w", dayColumnNames[i], err)
+			}
+			loggingTime.SetHours(dayWeekdays[i], hours)
+		}
+	}
+	return []*api.AddLoggingTime{loggingTime}, nil
+}
+
+// открывает файл временной затраты в редакторе и добавляет её в расписание
+func editAndAddLoggingTime(client api.API, scheduleId api.ScheduleId, path string) error {
+	var loggingTime *api.AddLoggingTime
+	err := editUntilValid(path, func() (validator, error) {
+		var err error
+		loggingTime, err = clifuncs.LoggingTimeFromFile(hoursParser)
+		return loggingTime, err
+	})
 	if err != nil {
 		return err
 	}
-	path, err := clifuncs.GenLoggingTimeFile()
+	loggingTimeResp, err := client.AddLoggingTime(scheduleId, loggingTime)
 	if err != nil {
 		return err
 	}
-	cmd := exec.Command(editor, path)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	err = cmd.Run()
+	return printer.PrintOne(loggingTimeResp, loggingTimeColumns)
+}
+
+func editLoggingTime(c *cli.Context) error {
+	client, err := clientConstructor.NewClient()
 	if err != nil {
 		return err
 	}
 	scheduleId := api.ScheduleId(scheduleId)
-	loggingTime, err := clifuncs.LoggingTimeFromFile()
+	loggingTimeId := api.LoggingTimeId(loggingTimeId)
+	loggingTime, err := client.DetailLoggingTime(scheduleId, loggingTimeId)
 	if err != nil {
 		return err
 	}
-	loggingTimeResp, err := client.AddLoggingTime(scheduleId, loggingTime)
+	err = loggingTime.StatusCode.Check(api.ActionEdit)
 	if err != nil {
 		return err
 	}
-	LoggingTimeJSON, err := json.Marshal(loggingTimeResp)
+	path, err := clifuncs.GenEditLoggingTimeFile(api.NewEditLoggingTime(loggingTime))
 	if err != nil {
 		return err
 	}
-	fmt.Printf("%s\n", LoggingTimeJSON)
-	return nil
+	var editLoggingTime *api.EditLoggingTime
+	err = editUntilValid(path, func() (validator, error) {
+		editLoggingTime, err = clifuncs.EditLoggingTimeFromFile(hoursParser)
+		return editLoggingTime, err
+	})
+	if err != nil {
+		return err
+	}
+	loggingTimeResp, err := client.UpdateLoggingTime(scheduleId, loggingTimeId, editLoggingTime)
+	if err != nil {
+		return err
+	}
+	return printer.PrintOne(loggingTimeResp, loggingTimeColumns)
+}
+
+// проверяет по статусу расписания, допустимо ли действие, до изменяющего запроса
+func checkScheduleStatus(client api.API, scheduleId api.ScheduleId, action api.Action) error {
+	schedule, err := client.DetailSchedule(scheduleId)
+	if err != nil {
+		return err
+	}
+	return schedule.StatusCode.Check(action)
+}
+
+// проверяет по статусу временной затраты, допустимо ли действие, до изменяющего запроса
+func checkLoggingTimeStatus(client api.API, scheduleId api.ScheduleId, loggingTimeId api.LoggingTimeId, action api.Action) error {
+	loggingTime, err := client.DetailLoggingTime(scheduleId, loggingTimeId)
+	if err != nil {
+		return err
+	}
+	return loggingTime.StatusCode.Check(action)
+}
+
+type validator interface {
+	ValidateWith(rules api.ValidationRules) error
+}
+
+// открывает файл в редакторе, пока прочитанное из него значение не пройдёт проверку.
+// Ошибки записываются комментариями в начало файла. Редактирование прерывается,
+// если пользователь очистил файл или повторно закрыл редактор, не изменив файл
+func editUntilValid(path string, read func() (validator, error)) error {
+	for attempt := 0; ; attempt++ {
+		before, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		err = runEditor(path)
+		if err != nil {
+			return err
+		}
+		after, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		value, err := read()
+		if errors.Is(err, clifuncs.ErrEmptyFile) {
+			return errors.New("редактирование отменено: файл пуст")
+		}
+		if err == nil {
+			err = value.ValidateWith(validationRules())
+		}
+		if err == nil {
+			return nil
+		}
+		if attempt > 0 && bytes.Equal(before, after) {
+			return err
+		}
+		messages := []string{"Исправьте ошибки и сохраните файл. Для отмены очистите файл или закройте редактор без изменений"}
+		validationErr := &api.ValidationError{}
+		if errors.As(err, &validationErr) {
+			for _, fieldErr := range validationErr.Errors {
+				messages = append(messages, fieldErrorMessage(fieldErr))
+			}
+		} else {
+			messages = append(messages, err.Error())
+		}
+		err = clifuncs.AnnotateLoggingTimeFile(messages)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func runEditor(path string) error {
+	cmd := exec.Command(editor, path)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	return cmd.Run()
 }
 
 func removeLoggingTime(c *cli.Context) error {
@@ -422,11 +1137,15 @@ func removeLoggingTime(c *cli.Context) error {
 	}
 	scheduleId := api.ScheduleId(scheduleId)
 	loggingTimeId := api.LoggingTimeId(loggingTimeId)
+	err = checkLoggingTimeStatus(client, scheduleId, loggingTimeId, api.ActionEdit)
+	if err != nil {
+		return err
+	}
 	err = client.DeleteLoggingTime(scheduleId, loggingTimeId)
 	if err != nil {
 		return err
 	}
-	fmt.Println("Временная затрата успешно удалена")
+	fmt.Fprintln(stderr, "Временная затрата успешно удалена")
 	return nil
 }
 
@@ -437,17 +1156,15 @@ func approveLoggingTime(c *cli.Context) error {
 	}
 	scheduleId := api.ScheduleId(scheduleId)
 	loggingTimeId := api.LoggingTimeId(loggingTimeId)
-	loggingTime, err := client.ApproveLoggingTime(scheduleId, loggingTimeId, adminComment)
+	err = checkLoggingTimeStatus(client, scheduleId, loggingTimeId, api.ActionApprove)
 	if err != nil {
 		return err
 	}
-	loggingTimeJSON, err := json.Marshal(loggingTime)
-
+	loggingTime, err := client.ApproveLoggingTime(scheduleId, loggingTimeId, adminComment)
 	if err != nil {
 		return err
 	}
-	fmt.Printf("%s\n\n", loggingTimeJSON)
-	return nil
+	return printer.PrintOne(loggingTime, loggingTimeColumns)
 }
 
 func declineLoggingTime(c *cli.Context) error {
@@ -457,15 +1174,134 @@ func declineLoggingTime(c *cli.Context) error {
 	}
 	scheduleId := api.ScheduleId(scheduleId)
 	loggingTimeId := api.LoggingTimeId(loggingTimeId)
+	err = checkLoggingTimeStatus(client, scheduleId, loggingTimeId, api.ActionDecline)
+	if err != nil {
+		return err
+	}
 	loggingTime, err := client.DeclineLoggingTime(scheduleId, loggingTimeId, adminComment)
 	if err != nil {
 		return err
 	}
-	loggingTimeJSON, err := json.Marshal(loggingTime)
+	return printer.PrintOne(loggingTime, loggingTimeColumns)
+}
+
+func projects(c *cli.Context) error {
+	client, err := clientConstructor.NewClient()
+	if err != nil {
+		return err
+	}
+	options := api.OptionsP{
+		Page:   page,
+		Size:   size,
+		Search: search,
+	}
+	projects, err := client.Projects(&options)
+	if err != nil {
+		return err
+	}
+	return printProjects(projects)
+}
+
+func workKinds(c *cli.Context) error {
+	client, err := clientConstructor.NewClient()
+	if err != nil {
+		return err
+	}
+	workKinds, err := client.WorkKinds(&api.OptionsWK{Search: search})
+	if err != nil {
+		return err
+	}
+	return printWorkKinds(workKinds)
+}
 
+func periods(c *cli.Context) error {
+	options := api.OptionsPD{
+		Page: page,
+		Size: size,
+	}
+	var err error
+	options.StartDate, options.EndDate, err = dateRange()
+	if err != nil {
+		return err
+	}
+	client, err := clientConstructor.NewClient()
+	if err != nil {
+		return err
+	}
+	periods, err := client.Periods(&options)
 	if err != nil {
 		return err
 	}
-	fmt.Printf("%s\n\n", loggingTimeJSON)
+	return printPeriods(periods)
+}
+
+// клиент с записью кассеты, журналом и выводом запросов по глобальным флагам
+func newClientInit() (*clifuncs.ClientInit, error) {
+	clientInit := &clifuncs.ClientInit{}
+	if cassettePath != "" {
+		var err error
+		recorder, err = cassette.New(cassettePath, &cassette.Options{
+			Mode:       cassette.Record,
+			HttpClient: &http.Client{Timeout: time.Minute},
+		})
+		if err != nil {
+			return nil, err
+		}
+		clientInit.HttpClient = recorder
+	}
+	if logLevel != "" {
+		level, err := logging.ParseLevel(logLevel)
+		if err != nil {
+			return nil, err
+		}
+		clientInit.Logger = logging.New(os.Stderr, level)
+	}
+	if dumpHTTP {
+		clientInit.Middlewares = []api.Middleware{api.DebugDump(os.Stderr)}
+	}
+	return clientInit, nil
+}
+
+// настройки входа с теми же журналом, middleware и записью кассеты, что и у клиента
+func authOptions() *auth.Options {
+	if clientInit, ok := clientConstructor.(*clifuncs.ClientInit); ok {
+		return clientInit.AuthOptions()
+	}
 	return nil
 }
+
+func newHoursParser() (api.HoursParser, error) {
+	if roundHours < 0 {
+		return api.HoursParser{}, errors.New("шаг округления --round-hours не может быть отрицательным")
+	}
+	parsedRounding, err := api.ParseRounding(rounding)
+	if err != nil {
+		return api.HoursParser{}, err
+	}
+	return api.HoursParser{Step: roundHours, Rounding: parsedRounding}, nil
+}
+
+// правила проверки временных затрат: шаг часов совпадает с округлением --round-hours,
+// без округления шаг не проверяется
+func validationRules() api.ValidationRules {
+	rules := api.DefaultValidationRules()
+	rules.HourStep = hoursParser.Step
+	return rules
+}
+
+// разбирает флаги --from и --to, пустой флаг даёт нулевую дату
+func dateRange() (from time.Time, to time.Time, err error) {
+	if dateFrom != "" {
+		from, err = time.Parse(dateLayout, dateFrom)
+		if err != nil {
+			return from, to, fmt.Errorf("неверный формат даты --from %q, ожидается ГГГГ-ММ-ДД", dateFrom)
+		}
+	}
+	if dateTo != "" {
+		to, err = time.Parse(dateLayout, dateTo)
+		if err != nil {
+			return from, to, fmt.Errorf("неверный формат даты --to %q, ожидается ГГГГ-ММ-ДД", dateTo)
+		}
+	}
+	return from, to, nil
+}
@@ -1,12 +1,25 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/urfave/cli"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"suftsdk/internal/auth"
+	"suftsdk/internal/clifuncs"
 	"suftsdk/pkg/api"
+	"suftsdk/pkg/suftfake"
 	"testing"
+	"time"
 )
 
 var fakeSchedule1 = api.Schedule{
@@ -19,10 +32,10 @@ var fakeSchedule1 = api.Schedule{
 	},
 	Id: 0,
 	Period: api.Period{
-		CloseDate:  "",
-		EndDate:    "",
+		CloseDate:  api.Date{},
+		EndDate:    api.Date{},
 		Id:         5,
-		StartDate:  "",
+		StartDate:  api.Date{},
 		WeekNumber: 2,
 	},
 	StatusCode: "22",
@@ -38,10 +51,10 @@ var fakeSchedule2 = api.Schedule{
 	},
 	Id: 0,
 	Period: api.Period{
-		CloseDate:  "",
-		EndDate:    "",
+		CloseDate:  api.Date{},
+		EndDate:    api.Date{},
 		Id:         5,
-		StartDate:  "",
+		StartDate:  api.Date{},
 		WeekNumber: 2,
 	},
 	StatusCode: "25",
@@ -91,6 +104,10 @@ type addScheduleFunc func() (*api.Schedule, error)
 type loggingTimeListFunc func() ([]*api.LoggingTime, error)
 type detailLoggingTimeFunc func() (*api.LoggingTime, error)
 type deleteLoggingTimeFunc func() error
+type updateLoggingTimeFunc func() (*api.LoggingTime, error)
+type projectsFunc func() ([]*api.Project, error)
+type workKindsFunc func() ([]*api.WorkKind, error)
+type periodsFunc func() ([]*api.Period, error)
 type submitForApproveScheduleFunc func() (*api.Schedule, error)
 type approveLoggingTimeFunc func() (*api.LoggingTime, error)
 type declineLoggingTimeFunc func() (*api.LoggingTime, error)
@@ -102,11 +119,18 @@ var respAddSchedule addScheduleFunc
 var respLoggingTimeList loggingTimeListFunc
 var respDetailLoggingTime detailLoggingTimeFunc
 var respDeleteLoggingTime deleteLoggingTimeFunc
+var respUpdateLoggingTime updateLoggingTimeFunc
+var respProjects projectsFunc
+var respWorkKinds workKindsFunc
+var respPeriods periodsFunc
 var respSubmitForApproveSchedule submitForApproveScheduleFunc
 var respApproveLoggingTime approveLoggingTimeFunc
 var respDeclineLoggingTime declineLoggingTimeFunc
 var respAddLoggingTime addLoggingTimeFunc
 
+// последняя временная затрата, переданная в AddLoggingTime
+var lastAddLoggingTime *api.AddLoggingTime
+
 var exitIndicator string
 
 func TestCliFunc(t *testing.T) {
@@ -132,6 +156,22 @@ func TestCliFunc(t *testing.T) {
 		assert.Equal(t, "", exitIndicator)
 		exitIndicator = ""
 	})
+	t.Run("Успешный вызов Schedules со всеми страницами", func(t *testing.T) {
+		args := []string{"", "scs", "--all"}
+		respSchedules = SuccessRespSchedules
+		err = app.Run(args)
+		require.NoError(t, err)
+		assert.Equal(t, "", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Ошибка при вызове Schedules со всеми страницами", func(t *testing.T) {
+		args := []string{"", "scs", "-a"}
+		respSchedules = ErrorRespSchedules
+		err = app.Run(args)
+		require.Error(t, err)
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
 	t.Run("Вызов Schedules с лишним аргументом", func(t *testing.T) {
 		args := []string{"", "scs", "fake"}
 		respSchedules = SuccessRespSchedules
@@ -156,6 +196,24 @@ func TestCliFunc(t *testing.T) {
 		assert.Equal(t, "", exitIndicator)
 		exitIndicator = ""
 	})
+	t.Run("Успешный вызов ScheduleDetail со сводкой", func(t *testing.T) {
+		args := []string{"", "sc", "-scid", "777", "--summary", "--norm", "8"}
+		respScheduleDetail = SuccessRespDetailSchedule
+		respLoggingTimeList = SuccessRespLoggingTimeList
+		err = app.Run(args)
+		require.NoError(t, err)
+		assert.Equal(t, "", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Ошибка при получении временных затрат для сводки", func(t *testing.T) {
+		args := []string{"", "sc", "-scid", "777", "--summary"}
+		respScheduleDetail = SuccessRespDetailSchedule
+		respLoggingTimeList = ErrorRespLoggingTimeList
+		err = app.Run(args)
+		require.Error(t, err)
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
 	t.Run("Ошибка при вызове метода ScheduleDetail", func(t *testing.T) {
 		args := []string{"", "sc", "-scid", "777"}
 		respScheduleDetail = ErrorRespDetailSchedule
@@ -228,6 +286,14 @@ func TestCliFunc(t *testing.T) {
 		assert.Equal(t, "", exitIndicator)
 		exitIndicator = ""
 	})
+	t.Run("Успешный вызов LoggingTimeList со всеми страницами", func(t *testing.T) {
+		args := []string{"", "lts", "-scid", "777", "--all"}
+		respLoggingTimeList = SuccessRespLoggingTimeList
+		err = app.Run(args)
+		require.NoError(t, err)
+		assert.Equal(t, "", exitIndicator)
+		exitIndicator = ""
+	})
 	t.Run("Ошибка при вызове LoggingTimeList", func(t *testing.T) {
 		args := []string{"", "lts", "-scid", "777"}
 		respLoggingTimeList = ErrorRespLoggingTimeList
@@ -262,6 +328,7 @@ func TestCliFunc(t *testing.T) {
 	})
 	t.Run("Успешный вызов DeleteLoggingTime", func(t *testing.T) {
 		args := []string{"", "rmlt", "-scid", "777", "-ltid", "777"}
+		respDetailLoggingTime = SuccessRespDetailLoggingTime
 		respDeleteLoggingTime = SuccessRespDeleteLoggingTime
 		err = app.Run(args)
 		require.NoError(t, err)
@@ -278,14 +345,133 @@ func TestCliFunc(t *testing.T) {
 	})
 	t.Run("Ошибка при вызове DeleteLoggingTime", func(t *testing.T) {
 		args := []string{"", "rmlt", "-scid", "777", "-ltid", "777"}
+		respDetailLoggingTime = SuccessRespDetailLoggingTime
 		respDeleteLoggingTime = ErrorRespDeleteLoggingTime
 		err = app.Run(args)
 		require.Error(t, err)
 		assert.Equal(t, "1", exitIndicator)
 		exitIndicator = ""
 	})
+	t.Run("Успешный вызов Week", func(t *testing.T) {
+		args := []string{"", "w", "-d", "2021-02-17"}
+		respPeriods = SuccessRespWeekPeriods
+		respSchedules = SuccessRespSchedules
+		err = app.Run(args)
+		require.NoError(t, err)
+		assert.Equal(t, "", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Вызов Week без расписания на неделю", func(t *testing.T) {
+		args := []string{"", "w", "-d", "2021-02-17"}
+		respPeriods = SuccessRespWeekPeriods
+		respSchedules = EmptyRespSchedules
+		err = app.Run(args)
+		require.NoError(t, err)
+		assert.Equal(t, "", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Успешный вызов Week с созданием расписания", func(t *testing.T) {
+		args := []string{"", "w", "-d", "2021-02-17", "--ensure"}
+		respPeriods = SuccessRespWeekPeriods
+		respSchedules = EmptyRespSchedules
+		respAddSchedule = SuccessRespAddSchedule
+		err = app.Run(args)
+		require.NoError(t, err)
+		assert.Equal(t, "", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Ошибка при создании расписания в Week", func(t *testing.T) {
+		args := []string{"", "w", "-d", "2021-02-17", "--ensure"}
+		respPeriods = SuccessRespWeekPeriods
+		respSchedules = EmptyRespSchedules
+		respAddSchedule = ErrorRespAddSchedule
+		err = app.Run(args)
+		require.Error(t, err)
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Вызов Week без периода", func(t *testing.T) {
+		args := []string{"", "w", "-d", "2021-03-17"}
+		respPeriods = SuccessRespWeekPeriods
+		err = app.Run(args)
+		require.ErrorIs(t, err, api.ErrNotFound)
+		assert.Equal(t, "нет периода, в который попадает дата 2021-03-17", errorMessage(err))
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Передача невалидной даты в Week", func(t *testing.T) {
+		args := []string{"", "w", "-d", "17.02.2021"}
+		err = app.Run(args)
+		require.Error(t, err)
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Успешный вызов Export", func(t *testing.T) {
+		args := []string{"", "exp", "--from", "2021-02-01", "--to", "2021-02-28", "-f", "markdown"}
+		respSchedules = SuccessRespSchedules
+		respLoggingTimeList = SuccessRespLoggingTimeList
+		err = app.Run(args)
+		require.NoError(t, err)
+		assert.Equal(t, "", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Успешный вызов Export в файл XLSX", func(t *testing.T) {
+		out := filepath.Join(t.TempDir(), "report.xlsx")
+		args := []string{"", "exp", "--schedules", "777", "--schedules", "778", "-f", "xlsx", "-o", out}
+		respScheduleDetail = SuccessRespDetailSchedule
+		respLoggingTimeList = SuccessRespLoggingTimeList
+		err = app.Run(args)
+		require.NoError(t, err)
+		assert.FileExists(t, out)
+		assert.Equal(t, "", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Вызов Export в XLSX без файла", func(t *testing.T) {
+		args := []string{"", "exp", "-f", "xlsx"}
+		err = app.Run(args)
+		require.Error(t, err)
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Ошибка при вызове Export", func(t *testing.T) {
+		args := []string{"", "exp", "-f", "json"}
+		respSchedules = ErrorRespSchedules
+		err = app.Run(args)
+		require.Error(t, err)
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Успешный вызов CopyWeek", func(t *testing.T) {
+		args := []string{"", "cw", "--from-schedule", "777", "--to-period", "5"}
+		respLoggingTimeList = SourceOnlyRespLoggingTimeList()
+		respSchedules = SuccessRespSchedules
+		respAddLoggingTime = SuccessRespAddLoggingTime
+		err = app.Run(args)
+		require.NoError(t, err)
+		assert.Equal(t, "", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Ошибка при создании строк в CopyWeek", func(t *testing.T) {
+		args := []string{"", "cw", "--from-schedule", "777", "--to-period", "6", "--zero-hours"}
+		respLoggingTimeList = SourceOnlyRespLoggingTimeList()
+		respSchedules = EmptyRespSchedules
+		respAddSchedule = SuccessRespAddSchedule
+		respAddLoggingTime = ErrorRespAddLoggingTime
+		err = app.Run(args)
+		require.Error(t, err)
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Вызов CopyWeek с взаимоисключающими флагами", func(t *testing.T) {
+		args := []string{"", "cw", "--from-schedule", "777", "--to-period", "5", "--keep-hours", "--zero-hours"}
+		err = app.Run(args)
+		require.Error(t, err)
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
 	t.Run("Успешный вызов SubmitForApproveSchedule", func(t *testing.T) {
 		args := []string{"", "s", "-scid", "777"}
+		respScheduleDetail = SuccessRespDetailSchedule
 		respSubmitForApproveSchedule = SuccessRespSubmitForApproveSchedule
 		err = app.Run(args)
 		require.NoError(t, err)
@@ -294,6 +480,7 @@ func TestCliFunc(t *testing.T) {
 	})
 	t.Run("Ошибка при вызове метода SubmitForApproveSchedule", func(t *testing.T) {
 		args := []string{"", "s", "-scid", "777"}
+		respScheduleDetail = SuccessRespDetailSchedule
 		respSubmitForApproveSchedule = ErrorRespSubmitForApproveSchedule
 		err = app.Run(args)
 		require.Error(t, err)
@@ -316,8 +503,18 @@ func TestCliFunc(t *testing.T) {
 		assert.Equal(t, "", exitIndicator)
 		exitIndicator = ""
 	})
+	t.Run("Отправка на утверждение расписания в недопустимом статусе", func(t *testing.T) {
+		args := []string{"", "s", "-scid", "777"}
+		respScheduleDetail = ToApproveRespDetailSchedule
+		respSubmitForApproveSchedule = SuccessRespSubmitForApproveSchedule
+		err = app.Run(args)
+		require.ErrorIs(t, err, api.ErrInvalidStatus)
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
 	t.Run("Успешный вызов ApproveLoggingTime", func(t *testing.T) {
 		args := []string{"", "aprv", "-scid", "777", "-ltid", "777"}
+		respDetailLoggingTime = SuccessRespDetailLoggingTime
 		respApproveLoggingTime = SuccessRespApproveLoggingTime
 		err = app.Run(args)
 		require.NoError(t, err)
@@ -334,14 +531,25 @@ func TestCliFunc(t *testing.T) {
 	})
 	t.Run("Ошибка при вызове ApproveLoggingTime", func(t *testing.T) {
 		args := []string{"", "aprv", "-scid", "777", "-ltid", "777"}
+		respDetailLoggingTime = SuccessRespDetailLoggingTime
 		respApproveLoggingTime = ErrorRespApproveLoggingTime
 		err = app.Run(args)
 		require.Error(t, err)
 		assert.Equal(t, "1", exitIndicator)
 		exitIndicator = ""
 	})
+	t.Run("Утверждение временной затраты в недопустимом статусе", func(t *testing.T) {
+		args := []string{"", "aprv", "-scid", "777", "-ltid", "777"}
+		respDetailLoggingTime = CreatedRespDetailLoggingTime
+		respApproveLoggingTime = SuccessRespApproveLoggingTime
+		err = app.Run(args)
+		require.ErrorIs(t, err, api.ErrInvalidStatus)
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
 	t.Run("Успешный вызов DeclineLoggingTime", func(t *testing.T) {
 		args := []string{"", "dcl", "-scid", "777", "-ltid", "777"}
+		respDetailLoggingTime = SuccessRespDetailLoggingTime
 		respDeclineLoggingTime = SuccessRespDeclineLoggingTime
 		err = app.Run(args)
 		require.NoError(t, err)
@@ -358,6 +566,7 @@ func TestCliFunc(t *testing.T) {
 	})
 	t.Run("Ошибка при вызове DeclineLoggingTime", func(t *testing.T) {
 		args := []string{"", "dcl", "-scid", "777", "-ltid", "777"}
+		respDetailLoggingTime = SuccessRespDetailLoggingTime
 		respDeclineLoggingTime = ErrorRespDeclineLoggingTime
 		err = app.Run(args)
 		require.Error(t, err)
@@ -366,6 +575,7 @@ func TestCliFunc(t *testing.T) {
 	})
 	t.Run("Успешный вызов AddLoggingTime", func(t *testing.T) {
 		args := []string{"", "al", "-scid", "777"}
+		respScheduleDetail = SuccessRespDetailSchedule
 		respAddLoggingTime = SuccessRespAddLoggingTime
 		err = app.Run(args)
 		//Тест не может записать в открытый файл и выпадает ошибка
@@ -375,6 +585,7 @@ func TestCliFunc(t *testing.T) {
 	})
 	t.Run("Ошибка при вызове метода AddLoggingTime", func(t *testing.T) {
 		args := []string{"", "al", "-scid", "777"}
+		respScheduleDetail = SuccessRespDetailSchedule
 		respAddLoggingTime = ErrorRespAddLoggingTime
 		//Тест не может записать в открытый файл и выпадает ошибка
 		err = app.Run(args)
@@ -382,6 +593,379 @@ func TestCliFunc(t *testing.T) {
 		assert.Equal(t, "1", exitIndicator)
 		exitIndicator = ""
 	})
+	t.Run("Успешный вызов EditLoggingTime", func(t *testing.T) {
+		restore := useTempConfigDir(t)
+		defer restore()
+		args := []string{"", "elt", "-scid", "777", "-ltid", "777", "-e", "true"}
+		respDetailLoggingTime = ValidRespDetailLoggingTime
+		respUpdateLoggingTime = SuccessRespUpdateLoggingTime
+		err = app.Run(args)
+		require.NoError(t, err)
+		assert.Equal(t, "", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Ошибка при вызове метода UpdateLoggingTime", func(t *testing.T) {
+		restore := useTempConfigDir(t)
+		defer restore()
+		args := []string{"", "elt", "-scid", "777", "-ltid", "777", "-e", "true"}
+		respDetailLoggingTime = ValidRespDetailLoggingTime
+		respUpdateLoggingTime = ErrorRespUpdateLoggingTime
+		err = app.Run(args)
+		require.Error(t, err)
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Невалидная временная затрата в EditLoggingTime", func(t *testing.T) {
+		restore := useTempConfigDir(t)
+		defer restore()
+		args := []string{"", "elt", "-scid", "777", "-ltid", "777", "-e", "true"}
+		respDetailLoggingTime = SuccessRespDetailLoggingTime
+		respUpdateLoggingTime = SuccessRespUpdateLoggingTime
+		err = app.Run(args)
+		require.ErrorIs(t, err, api.ErrValidation)
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Успешный вызов AddLoggingTime после исправления файла", func(t *testing.T) {
+		restore := useTempConfigDir(t)
+		defer restore()
+		editorPath := writeEditorScript(t, `{"day1Time": 8, "projectId": 1, "task": "task", "workKindId": 2}`)
+		args := []string{"", "al", "-scid", "777", "-e", editorPath}
+		respScheduleDetail = SuccessRespDetailSchedule
+		respAddLoggingTime = SuccessRespAddLoggingTime
+		err = app.Run(args)
+		require.NoError(t, err)
+		assert.Equal(t, "", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Часы в виде времени в файле AddLoggingTime", func(t *testing.T) {
+		restore := useTempConfigDir(t)
+		defer restore()
+		editorPath := writeEditorScript(t, `{"day1Time": "7:30", "day2Time": "8h", "projectId": 1, "task": "task", "workKindId": 2}`)
+		args := []string{"", "al", "-scid", "777", "-e", editorPath}
+		respScheduleDetail = SuccessRespDetailSchedule
+		respAddLoggingTime = SuccessRespAddLoggingTime
+		err = app.Run(args)
+		require.NoError(t, err)
+		assert.Equal(t, 15.5, lastAddLoggingTime.TotalHours())
+		assert.Equal(t, "", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Невалидная временная затрата в AddLoggingTime", func(t *testing.T) {
+		restore := useTempConfigDir(t)
+		defer restore()
+		args := []string{"", "al", "-scid", "777", "-e", "true"}
+		respScheduleDetail = SuccessRespDetailSchedule
+		respAddLoggingTime = SuccessRespAddLoggingTime
+		err = app.Run(args)
+		require.ErrorIs(t, err, api.ErrValidation)
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Отмена AddLoggingTime очисткой файла", func(t *testing.T) {
+		restore := useTempConfigDir(t)
+		defer restore()
+		editorPath := writeEditorScript(t, "")
+		args := []string{"", "al", "-scid", "777", "-e", editorPath}
+		respScheduleDetail = SuccessRespDetailSchedule
+		respAddLoggingTime = SuccessRespAddLoggingTime
+		err = app.Run(args)
+		require.Error(t, err)
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Успешный вызов AddLoggingTime с флагами", func(t *testing.T) {
+		args := []string{"", "al", "-scid", "777", "--project", "1", "--work-kind", "2", "--task", "task", "--hours", "8,8,8,8,8,0,0"}
+		respScheduleDetail = SuccessRespDetailSchedule
+		respAddLoggingTime = SuccessRespAddLoggingTime
+		err = app.Run(args)
+		require.NoError(t, err)
+		assert.Equal(t, "", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Невалидная временная затрата в флагах AddLoggingTime", func(t *testing.T) {
+		args := []string{"", "al", "-scid", "777", "--project", "1", "--task", "task", "--mon", "8"}
+		respScheduleDetail = SuccessRespDetailSchedule
+		respAddLoggingTime = SuccessRespAddLoggingTime
+		err = app.Run(args)
+		require.ErrorIs(t, err, api.ErrValidation)
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Флаги --hours и --mon в AddLoggingTime", func(t *testing.T) {
+		args := []string{"", "al", "-scid", "777", "--hours", "8,8,8,8,8,0,0", "--mon", "8"}
+		err = app.Run(args)
+		require.EqualError(t, err, "флаг --hours нельзя указывать вместе с --mon")
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Неверное количество значений --hours в AddLoggingTime", func(t *testing.T) {
+		args := []string{"", "al", "-scid", "777", "--hours", "8,8,8"}
+		err = app.Run(args)
+		require.Error(t, err)
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Успешный вызов AddLoggingTime из стандартного ввода", func(t *testing.T) {
+		stdin = strings.NewReader(`{"day1Time": 8, "projectId": 1, "task": "task1", "workKindId": 2}
+{"day2Time": 4, "projectId": 1, "task": "task2", "workKindId": 2}
+`)
+		defer func() { stdin = os.Stdin }()
+		added := 0
+		args := []string{"", "al", "-scid", "777", "--stdin"}
+		respScheduleDetail = SuccessRespDetailSchedule
+		respAddLoggingTime = func() (*api.LoggingTime, error) {
+			added++
+			return SuccessRespAddLoggingTime()
+		}
+		err = app.Run(args)
+		require.NoError(t, err)
+		assert.Equal(t, 2, added)
+		assert.Equal(t, "", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Невалидная временная затрата в стандартном вводе AddLoggingTime", func(t *testing.T) {
+		stdin = strings.NewReader(`[{"day1Time": 8, "projectId": 1, "task": "task1", "workKindId": 2}, {"day1Time": 8}]`)
+		defer func() { stdin = os.Stdin }()
+		args := []string{"", "al", "-scid", "777", "--stdin"}
+		respScheduleDetail = SuccessRespDetailSchedule
+		respAddLoggingTime = SuccessRespAddLoggingTime
+		err = app.Run(args)
+		require.ErrorIs(t, err, api.ErrValidation)
+		assert.True(t, strings.HasPrefix(errorMessage(err), "объект 2: временная затрата не прошла проверку: "))
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Часы в виде времени и с округлением в AddLoggingTime", func(t *testing.T) {
+		stdin = strings.NewReader(`{"day1Time": "7:30", "day2Time": "1h20m", "day3Time": 2.1, "projectId": 1, "task": "task", "workKindId": 2}`)
+		defer func() { stdin = os.Stdin }()
+		args := []string{"", "--round-hours", "0.25", "al", "-scid", "777", "--stdin"}
+		respScheduleDetail = SuccessRespDetailSchedule
+		respAddLoggingTime = SuccessRespAddLoggingTime
+		err = app.Run(args)
+		require.NoError(t, err)
+		assert.Equal(t, 7.5, lastAddLoggingTime.Day1Time)
+		assert.Equal(t, 1.25, lastAddLoggingTime.Day2Time)
+		assert.Equal(t, 2.0, lastAddLoggingTime.Day3Time)
+		assert.Equal(t, "", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Часы в виде времени во флагах AddLoggingTime", func(t *testing.T) {
+		args := []string{"", "al", "-scid", "777", "--project", "1", "--work-kind", "2", "--task", "task", "--mon", "7:30", "--fri", "45m"}
+		respScheduleDetail = SuccessRespDetailSchedule
+		respAddLoggingTime = SuccessRespAddLoggingTime
+		err = app.Run(args)
+		require.NoError(t, err)
+		assert.Equal(t, 8.25, lastAddLoggingTime.TotalHours())
+		assert.Equal(t, "", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Часы без округления не проверяются по шагу в AddLoggingTime", func(t *testing.T) {
+		args := []string{"", "al", "-scid", "777", "--project", "1", "--work-kind", "2", "--task", "task", "--mon", "1:20"}
+		respScheduleDetail = SuccessRespDetailSchedule
+		respAddLoggingTime = SuccessRespAddLoggingTime
+		err = app.Run(args)
+		require.NoError(t, err)
+		assert.InDelta(t, 4.0/3, lastAddLoggingTime.Day1Time, 1e-9)
+		assert.Equal(t, "", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Часы с шагом округления --round-hours в AddLoggingTime", func(t *testing.T) {
+		args := []string{"", "--round-hours", "0.1", "al", "-scid", "777", "--project", "1", "--work-kind", "2", "--task", "task", "--mon", "1:20"}
+		respScheduleDetail = SuccessRespDetailSchedule
+		respAddLoggingTime = SuccessRespAddLoggingTime
+		err = app.Run(args)
+		require.NoError(t, err)
+		assert.InDelta(t, 1.3, lastAddLoggingTime.Day1Time, 1e-9)
+		assert.Equal(t, "", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Неоднозначные часы во флагах AddLoggingTime", func(t *testing.T) {
+		args := []string{"", "al", "-scid", "777", "--project", "1", "--work-kind", "2", "--task", "task", "--mon", "1.30"}
+		err = app.Run(args)
+		require.ErrorIs(t, err, api.ErrInvalidHours)
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Неверный способ округления", func(t *testing.T) {
+		args := []string{"", "--rounding", "half", "al", "-scid", "777", "--mon", "8"}
+		err = app.Run(args)
+		require.Error(t, err)
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Пустой стандартный ввод AddLoggingTime", func(t *testing.T) {
+		stdin = strings.NewReader(" \n")
+		defer func() { stdin = os.Stdin }()
+		args := []string{"", "al", "-scid", "777", "--stdin"}
+		err = app.Run(args)
+		require.ErrorIs(t, err, clifuncs.ErrNoInput)
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Ошибка при получении временной затраты в EditLoggingTime", func(t *testing.T) {
+		args := []string{"", "elt", "-scid", "777", "-ltid", "777", "-e", "true"}
+		respDetailLoggingTime = ErrorRespDetailLoggingTime
+		respUpdateLoggingTime = SuccessRespUpdateLoggingTime
+		err = app.Run(args)
+		require.Error(t, err)
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Вызов EditLoggingTime без аргумента", func(t *testing.T) {
+		args := []string{"", "elt", "-scid", "777"}
+		err = app.Run(args)
+		require.Error(t, err)
+		assert.Equal(t, "", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Успешный вызов Import", func(t *testing.T) {
+		path := writeImportFile(t, "projectId;workKindId;task;day1Time\n1;21;task;8\n")
+		args := []string{"", "imp", "-scid", "777", "--delimiter", ";", path}
+		respScheduleDetail = SuccessRespDetailSchedule
+		respAddLoggingTime = SuccessRespAddLoggingTime
+		err = app.Run(args)
+		require.NoError(t, err)
+		assert.Equal(t, "", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Вызов Import без добавления строк", func(t *testing.T) {
+		path := writeImportFile(t, "projectId,workKindId,task,day1Time\n1,21,task,8\n")
+		args := []string{"", "imp", "-scid", "777", "--dry-run", path}
+		respScheduleDetail = SuccessRespDetailSchedule
+		respAddLoggingTime = ErrorRespAddLoggingTime
+		err = app.Run(args)
+		require.NoError(t, err)
+		assert.Equal(t, "", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Ошибка в строках Import", func(t *testing.T) {
+		path := writeImportFile(t, "projectId,workKindId,task,day1Time\n1,21,task,8\n1,21,,-8\n")
+		args := []string{"", "imp", "-scid", "777", path}
+		respScheduleDetail = SuccessRespDetailSchedule
+		respAddLoggingTime = SuccessRespAddLoggingTime
+		err = app.Run(args)
+		require.Error(t, err)
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Вызов Import без файла", func(t *testing.T) {
+		args := []string{"", "imp", "-scid", "777"}
+		err = app.Run(args)
+		require.Error(t, err)
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Работа с шаблонами временных затрат", func(t *testing.T) {
+		restore := useTempConfigDir(t)
+		defer restore()
+		editorPath := writeEditorScript(t, `{"name": "daily", "day1Time": 8, "projectId": 1, "task": "task", "workKindId": 2}`)
+		err = app.Run([]string{"", "tpl", "save", "-e", editorPath, "daily"})
+		require.NoError(t, err)
+
+		templates, err := clifuncs.Templates()
+		require.NoError(t, err)
+		require.Len(t, templates, 1)
+		assert.Equal(t, "daily", templates[0].Name)
+		assert.Equal(t, 8.0, templates[0].Day1Time)
+
+		require.NoError(t, app.Run([]string{"", "tpl", "list"}))
+		require.NoError(t, app.Run([]string{"", "tpl", "show", "daily"}))
+
+		respScheduleDetail = SuccessRespDetailSchedule
+		respAddLoggingTime = SuccessRespAddLoggingTime
+		require.NoError(t, app.Run([]string{"", "tpl", "apply", "-scid", "777", "-e", "true", "daily"}))
+
+		require.NoError(t, app.Run([]string{"", "tpl", "delete", "daily"}))
+		err = app.Run([]string{"", "tpl", "show", "daily"})
+		require.ErrorIs(t, err, clifuncs.ErrTemplateNotFound)
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Переименование шаблона в редакторе", func(t *testing.T) {
+		restore := useTempConfigDir(t)
+		defer restore()
+		editorPath := writeEditorScript(t, `{"name": "other", "day1Time": 8, "projectId": 1, "task": "task", "workKindId": 2}`)
+		require.NoError(t, app.Run([]string{"", "tpl", "save", "-e", editorPath, "daily"}))
+
+		templates, err := clifuncs.Templates()
+		require.NoError(t, err)
+		require.Len(t, templates, 1)
+		assert.Equal(t, "daily", templates[0].Name)
+		assert.Equal(t, 8.0, templates[0].Day1Time)
+	})
+	t.Run("Вызов шаблона без имени", func(t *testing.T) {
+		restore := useTempConfigDir(t)
+		defer restore()
+		err = app.Run([]string{"", "tpl", "delete"})
+		require.Error(t, err)
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Сохранение невалидного шаблона", func(t *testing.T) {
+		restore := useTempConfigDir(t)
+		defer restore()
+		err = app.Run([]string{"", "tpl", "save", "-e", "true", "empty"})
+		require.ErrorIs(t, err, api.ErrValidation)
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Успешный вызов Projects", func(t *testing.T) {
+		args := []string{"", "prj", "-q", "суфт", "-s", "10"}
+		respProjects = SuccessRespProjects
+		err = app.Run(args)
+		require.NoError(t, err)
+		assert.Equal(t, "", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Ошибка при вызове метода Projects", func(t *testing.T) {
+		args := []string{"", "prj"}
+		respProjects = ErrorRespProjects
+		err = app.Run(args)
+		require.Error(t, err)
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Успешный вызов WorkKinds", func(t *testing.T) {
+		args := []string{"", "wk", "--search", "разраб"}
+		respWorkKinds = SuccessRespWorkKinds
+		err = app.Run(args)
+		require.NoError(t, err)
+		assert.Equal(t, "", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Ошибка при вызове метода WorkKinds", func(t *testing.T) {
+		args := []string{"", "wk"}
+		respWorkKinds = ErrorRespWorkKinds
+		err = app.Run(args)
+		require.Error(t, err)
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Успешный вызов Periods", func(t *testing.T) {
+		args := []string{"", "pds", "--from", "2021-02-01", "--to", "2021-02-28"}
+		respPeriods = SuccessRespPeriods
+		err = app.Run(args)
+		require.NoError(t, err)
+		assert.Equal(t, "", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Передача невалидной даты в Periods", func(t *testing.T) {
+		args := []string{"", "pds", "--from", "01.02.2021"}
+		respPeriods = SuccessRespPeriods
+		err = app.Run(args)
+		require.Error(t, err)
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
+	t.Run("Ошибка при вызове метода Periods", func(t *testing.T) {
+		args := []string{"", "pds"}
+		respPeriods = ErrorRespPeriods
+		err = app.Run(args)
+		require.Error(t, err)
+		assert.Equal(t, "1", exitIndicator)
+		exitIndicator = ""
+	})
 	t.Run("Передача невалидного флага в AddLoggingTime", func(t *testing.T) {
 		args := []string{"", "al", "-scid", "ар"}
 		respAddLoggingTime = SuccessRespAddLoggingTime
@@ -393,6 +977,343 @@ func TestCliFunc(t *testing.T) {
 
 }
 
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"Обычная ошибка", errors.New("fake"), exitCodeError},
+		{"401", &api.APIError{StatusCode: 401}, exitCodeUnauthorized},
+		{"403", &api.APIError{StatusCode: 403}, exitCodeForbidden},
+		{"404", &api.APIError{StatusCode: 404}, exitCodeNotFound},
+		{"409", &api.APIError{StatusCode: 409}, exitCodeConflict},
+		{"502", &api.APIError{StatusCode: 502}, exitCodeServerError},
+		{"Неверный пароль", &auth.AuthError{StatusCode: 401}, exitCodeUnauthorized},
+		{"502 при входе", &auth.AuthError{StatusCode: 502}, exitCodeServerError},
+		{"Недопустимый статус", &api.StatusError{Action: api.ActionSubmit, Status: api.ToApprove}, exitCodeConflict},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.code, exitCode(test.err))
+		})
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	assert.Equal(t, "fake", errorMessage(errors.New("fake")))
+	assert.Equal(t,
+		"объект не найден, проверьте переданные id (Schedule not found)",
+		errorMessage(&api.APIError{StatusCode: 404, RawBody: []byte("Schedule not found")}),
+	)
+	assert.Equal(t,
+		"недостаточно прав для выполнения операции",
+		errorMessage(&api.APIError{StatusCode: 403}),
+	)
+	assert.Equal(t,
+		"неверный логин или пароль",
+		errorMessage(&auth.AuthError{StatusCode: 401, Body: []byte("Bad credentials")}),
+	)
+	assert.Equal(t,
+		"утверждение недоступно: статус «Создано»",
+		errorMessage(&api.StatusError{Action: api.ActionApprove, Status: api.Created}),
+	)
+	assert.Equal(t,
+		"временная затрата не прошла проверку: task: обязательное поле; day1Time: не больше 24 ч. за день",
+		errorMessage(&api.ValidationError{Errors: []api.FieldError{
+			{Field: "task", Code: api.CodeRequired},
+			{Field: "day1Time", Code: api.CodeMaxDayHours, Limit: 24},
+		}}),
+	)
+	assert.Equal(t,
+		"объект 2: объект не найден, проверьте переданные id",
+		errorMessage(&objectError{Index: 2, Err: &api.APIError{StatusCode: 404}}),
+	)
+	assert.Equal(t,
+		"нет расписания на период 354",
+		errorMessage(&api.ScheduleNotFoundError{PeriodId: 354}),
+	)
+}
+
+func TestPrintSummary(t *testing.T) {
+	schedule := fakeSchedule1
+	schedule.Id = 777
+	schedule.StatusCode = api.Created
+	schedule.Period = api.Period{Id: 354, WeekNumber: 7, StartDate: api.NewDate(2021, 2, 15), EndDate: api.NewDate(2021, 2, 21)}
+	loggingTime := fakeLoggingTime1
+	loggingTime.StatusCode = api.Created
+	summary := api.Summarize(schedule.Period, []*api.LoggingTime{&loggingTime}, 40)
+
+	var out bytes.Buffer
+	require.NoError(t, printSummary(&out, &schedule, summary))
+	assert.Contains(t, out.String(), "Расписание 777, неделя 7 (2021-02-15 - 2021-02-21), статус: Создано")
+	assert.Contains(t, out.String(), "Ср     2021-02-17  1")
+	assert.Contains(t, out.String(), "Итого              5 из 40")
+	assert.Contains(t, out.String(), "До нормы не хватает 35 ч.")
+}
+
+func TestOutputFormats(t *testing.T) {
+	clientConstructor = fakeClientInit{}
+	respLoggingTimeList = SuccessRespLoggingTimeList
+	var out bytes.Buffer
+	stdout = &out
+	defer func() { stdout = os.Stdout }()
+	run := func(args ...string) string {
+		out.Reset()
+		app, err := cliFunc()
+		require.NoError(t, err)
+		require.NoError(t, app.Run(append([]string{""}, args...)))
+		return out.String()
+	}
+
+	table := run("lts", "--scid", "777")
+	assert.Contains(t, table, "ID  Проект  Вид работ  Задача  Статус  Пн  Вт  Ср  Чт  Пт  Сб  Вс  Итого")
+	assert.Contains(t, table, "0   0       0          fake1   fake1   1   2   1   0   1   0   0   5")
+	assert.NotContains(t, table, "adminEmployee")
+
+	assert.Equal(t, "fake1 5\nfake2 6\n",
+		run("--output", "template", "--template", "{{.Task}} {{.TotalHours}}", "lts", "--scid", "777"))
+	assert.Equal(t, "{\"task\":\"fake1\",\"total\":5}\n{\"task\":\"fake2\",\"total\":6}\n",
+		run("--output", "ndjson", "--fields", "task,total", "lts", "--scid", "777"))
+	assert.Contains(t, run("--output", "yaml", "lts", "--scid", "777"), "- adminEmployee:\n")
+
+	app, err := cliFunc()
+	require.NoError(t, err)
+	require.Error(t, app.Run([]string{"", "--output", "xml", "lts", "--scid", "777"}))
+	require.Error(t, app.Run([]string{"", "--fields", "unknown", "lts", "--scid", "777"}))
+}
+
+// создаёт CSV-файл для импорта
+func writeImportFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "week.csv")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+// создаёт исполняемый скрипт, который вместо редактора записывает content в файл
+func writeEditorScript(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "editor.sh")
+	script := fmt.Sprintf("#!/bin/sh\nprintf '%%s' '%s' > \"$1\"\n", content)
+	require.NoError(t, os.WriteFile(path, []byte(script), 0755))
+	return path
+}
+
+// подменяет каталог конфигурации пользователя временным каталогом
+func useTempConfigDir(t *testing.T) func() {
+	tempDir := t.TempDir()
+	restore := map[string]*string{}
+	for _, name := range []string{"HOME", "XDG_CONFIG_HOME"} {
+		if value, ok := os.LookupEnv(name); ok {
+			restore[name] = &value
+		} else {
+			restore[name] = nil
+		}
+		require.NoError(t, os.Setenv(name, tempDir))
+	}
+	configDir, err := os.UserConfigDir()
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "suft"), 0755))
+	return func() {
+		for name, value := range restore {
+			if value != nil {
+				os.Setenv(name, *value)
+			} else {
+				os.Unsetenv(name)
+			}
+		}
+	}
+}
+
+// подставляет в CLI клиента suftfake
+type suftfakeInit struct {
+	client *suftfake.Client
+}
+
+func (f suftfakeInit) NewClient() (client api.API, err error) {
+	return f.client, nil
+}
+
+func TestWorkflowWithFakeServer(t *testing.T) {
+	server := suftfake.NewServer()
+	boss := server.AddEmployee(api.Employee{Email: "boss@example.com"}, 0)
+	user := server.AddEmployee(api.Employee{Email: "user@example.com"}, boss.Id)
+	period := server.AddWeeks(time.Date(2021, 2, 15, 0, 0, 0, 0, time.UTC), 1)[0]
+	defer func() { clientConstructor = fakeClientInit{} }()
+	run := func(client *suftfake.Client, args ...string) {
+		clientConstructor = suftfakeInit{client: client}
+		app, err := cliFunc()
+		require.NoError(t, err)
+		require.NoError(t, app.Run(append([]string{"", "--output", "ndjson"}, args...)))
+	}
+
+	run(server.Client(user.Id), "as", "--pid", fmt.Sprint(period.Id))
+	schedules, err := server.Client(user.Id).Schedules(nil)
+	require.NoError(t, err)
+	require.Len(t, schedules, 1)
+	scheduleId := fmt.Sprint(schedules[0].Id)
+
+	run(server.Client(user.Id), "al", "-scid", scheduleId, "--project", "1", "--work-kind", "2", "--task", "task", "--hours", "8,8,8,8,8,0,0")
+	run(server.Client(user.Id), "s", "-scid", scheduleId)
+	loggingTimes := server.LoggingTimes(api.ScheduleId(schedules[0].Id))
+	require.Len(t, loggingTimes, 1)
+	assert.Equal(t, api.ToApprove, loggingTimes[0].StatusCode)
+
+	run(server.Client(boss.Id), "aprv", "-scid", scheduleId, "-ltid", fmt.Sprint(loggingTimes[0].Id), "-c", "ok")
+	schedule, _ := server.Schedule(api.ScheduleId(schedules[0].Id))
+	assert.Equal(t, api.Approved, schedule.StatusCode)
+}
+
+func TestCopyWeekOutput(t *testing.T) {
+	server := suftfake.NewServer()
+	user := server.AddEmployee(api.Employee{Email: "user@example.com"}, 0)
+	periods := server.AddWeeks(time.Date(2021, 2, 15, 0, 0, 0, 0, time.UTC), 2)
+	client := server.Client(user.Id)
+	schedule, err := client.AddSchedule(api.PeriodId(periods[0].Id))
+	require.NoError(t, err)
+	_, err = schedule.AddLoggingTime(&api.AddLoggingTime{Day1Time: 8, ProjectId: 1, Task: "task", WorkKindId: 2})
+	require.NoError(t, err)
+
+	clientConstructor = suftfakeInit{client: client}
+	var out, errOut bytes.Buffer
+	stdout, stderr = &out, &errOut
+	defer func() {
+		clientConstructor = fakeClientInit{}
+		stdout, stderr = os.Stdout, os.Stderr
+	}()
+	app, err := cliFunc()
+	require.NoError(t, err)
+	require.NoError(t, app.Run([]string{"", "--output", "json", "copy-week",
+		"--from-schedule", fmt.Sprint(schedule.Id), "--to-period", fmt.Sprint(periods[1].Id)}))
+
+	lines := []copyWeekLine{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &lines))
+	require.Len(t, lines, 1)
+	assert.Equal(t, "task", lines[0].Task)
+	assert.Equal(t, "создано", lines[0].Result)
+	assert.NotZero(t, lines[0].LoggingTimeId)
+	assert.Empty(t, errOut.String())
+}
+
+func TestImportOutput(t *testing.T) {
+	clientConstructor = fakeClientInit{}
+	respScheduleDetail = SuccessRespDetailSchedule
+	respAddLoggingTime = SuccessRespAddLoggingTime
+	var out bytes.Buffer
+	stdout = &out
+	defer func() { stdout = os.Stdout }()
+	path := writeImportFile(t, "projectId,workKindId,task,day1Time\n1,21,task,8\nx,21,task,8\n")
+
+	app, err := cliFunc()
+	require.NoError(t, err)
+	require.NoError(t, app.Run([]string{"", "--output", "json", "imp", "-scid", "777", "--dry-run", path}))
+	lines := []importLine{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &lines))
+	require.Len(t, lines, 2)
+	assert.Equal(t, 2, lines[0].Line)
+	assert.Equal(t, "task", lines[0].LoggingTime.Task)
+	assert.Empty(t, lines[0].Error)
+	assert.Equal(t, `неверный id проекта "x"`, lines[1].Error)
+
+	out.Reset()
+	path = writeImportFile(t, "projectId,workKindId,task,day1Time\n1,21,task,8\n")
+	require.NoError(t, app.Run([]string{"", "imp", "-scid", "777", path}))
+	assert.Contains(t, out.String(), "Строка  Результат  ID")
+	assert.Contains(t, out.String(), "добавлена")
+}
+
+func TestImportPartialMapping(t *testing.T) {
+	clientConstructor = fakeClientInit{}
+	respScheduleDetail = SuccessRespDetailSchedule
+	var out bytes.Buffer
+	stdout = &out
+	defer func() { stdout = os.Stdout }()
+	mapping := filepath.Join(t.TempDir(), "mapping.json")
+	require.NoError(t, os.WriteFile(mapping, []byte(`{"project": "Проект", "workKind": "Вид работ", "task": "Задача", "days": ["Пн"]}`), 0644))
+	path := writeImportFile(t, "Проект;Вид работ;Задача;commentEmployee;Пн\n1;21;task;comment;8\n")
+
+	app, err := cliFunc()
+	require.NoError(t, err)
+	require.NoError(t, app.Run([]string{"", "--output", "json", "imp", "-scid", "777", "--dry-run",
+		"--delimiter", ";", "--mapping", mapping, path}))
+	lines := []importLine{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &lines))
+	require.Len(t, lines, 1)
+	assert.Empty(t, lines[0].Error)
+	assert.Equal(t, "comment", lines[0].LoggingTime.CommentEmployee)
+	assert.Equal(t, 8.0, lines[0].LoggingTime.Day1Time)
+}
+
+func TestEditorFileHours(t *testing.T) {
+	restore := useTempConfigDir(t)
+	defer restore()
+	server := suftfake.NewServer()
+	user := server.AddEmployee(api.Employee{Email: "user@example.com"}, 0)
+	period := server.AddWeeks(time.Date(2021, 2, 15, 0, 0, 0, 0, time.UTC), 1)[0]
+	client := server.Client(user.Id)
+	schedule, err := client.AddSchedule(api.PeriodId(period.Id))
+	require.NoError(t, err)
+	loggingTime, err := schedule.AddLoggingTime(&api.AddLoggingTime{Day1Time: 7.5, Day2Time: 8, ProjectId: 1, Task: "task", WorkKindId: 2})
+	require.NoError(t, err)
+
+	// редактор сохраняет копию файла и не меняет его
+	captured := filepath.Join(t.TempDir(), "captured.json")
+	editorPath := filepath.Join(t.TempDir(), "editor.sh")
+	require.NoError(t, os.WriteFile(editorPath, []byte(fmt.Sprintf("#!/bin/sh\ncp \"$1\" '%s'\n", captured)), 0755))
+	clientConstructor = suftfakeInit{client: client}
+	defer func() { clientConstructor = fakeClientInit{} }()
+	app, err := cliFunc()
+	require.NoError(t, err)
+	require.NoError(t, app.Run([]string{"", "elt", "-scid", fmt.Sprint(schedule.Id), "-ltid", fmt.Sprint(loggingTime.Id), "-e", editorPath}))
+
+	data, err := os.ReadFile(captured)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"day1Time": "7h30m"`)
+	assert.Contains(t, string(data), `"day2Time": "8h"`)
+	assert.Contains(t, string(data), `"day3Time": "0"`)
+	updated, err := client.DetailLoggingTime(api.ScheduleId(schedule.Id), api.LoggingTimeId(loggingTime.Id))
+	require.NoError(t, err)
+	assert.Equal(t, 7.5, updated.Day1Time)
+	assert.Equal(t, 8.0, updated.Day2Time)
+}
+
+func TestRefreshConfigUsesClientInit(t *testing.T) {
+	restore := useTempConfigDir(t)
+	defer restore()
+	configDir, err := os.UserConfigDir()
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "suft"), 0755))
+	config := `{"Token":{"access_token":"old","refresh_token":"refresh"},"DateRefresh":"2021-02-15T00:00:00Z"}`
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "suft", "suft_config.json"), []byte(config), 0644))
+
+	httpClient := api.HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+		header := http.Header{}
+		if strings.HasSuffix(req.URL.Path, auth.RefreshURL) {
+			header.Add("Set-Cookie", "Access-token=new")
+			header.Add("Set-Cookie", "Refresh-token=new-refresh")
+		}
+		return &http.Response{StatusCode: http.StatusOK, Header: header, Body: io.NopCloser(strings.NewReader("[]")), Request: req}, nil
+	})
+	var urls []string
+	record := func(next api.HttpClient) api.HttpClient {
+		return api.HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+			urls = append(urls, req.Method+" "+req.URL.Path)
+			return next.Do(req)
+		})
+	}
+	clientConstructor = &clifuncs.ClientInit{HttpClient: httpClient, Middlewares: []api.Middleware{record}}
+	defer func() { clientConstructor = fakeClientInit{} }()
+	app, err := cliFunc()
+	require.NoError(t, err)
+	require.NoError(t, app.Run([]string{"", "--output", "json", "scs"}))
+
+	require.Len(t, urls, 2)
+	assert.Equal(t, "PUT /tools/suft/api/v1/security/refresh-token", urls[0])
+	assert.Equal(t, "GET /tools/suft/api/v1/api/v1/schedules", urls[1])
+	data, err := os.ReadFile(filepath.Join(configDir, "suft", "suft_config.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "new-refresh")
+}
+
 type fakeClientInit struct{}
 
 func (f fakeClientInit) NewClient() (client api.API, err error) {
@@ -419,6 +1340,7 @@ func (f *fakeClient) LoggingTimeList(scheduleId api.ScheduleId, options *api.Opt
 }
 
 func (f *fakeClient) AddLoggingTime(scheduleId api.ScheduleId, loggingTime *api.AddLoggingTime) (*api.LoggingTime, error) {
+	lastAddLoggingTime = loggingTime
 	return respAddLoggingTime()
 }
 
@@ -426,6 +1348,10 @@ func (f *fakeClient) DetailLoggingTime(scheduleId api.ScheduleId, loggingTimeId
 	return respDetailLoggingTime()
 }
 
+func (f *fakeClient) UpdateLoggingTime(scheduleId api.ScheduleId, loggingTimeId api.LoggingTimeId, loggingTime *api.EditLoggingTime) (*api.LoggingTime, error) {
+	return respUpdateLoggingTime()
+}
+
 func (f *fakeClient) DeleteLoggingTime(scheduleId api.ScheduleId, loggingTimeId api.LoggingTimeId) error {
 	return respDeleteLoggingTime()
 }
@@ -442,6 +1368,73 @@ func (f *fakeClient) DeclineLoggingTime(scheduleId api.ScheduleId, loggingTimeId
 	return respDeclineLoggingTime()
 }
 
+func (f *fakeClient) SchedulesContext(ctx context.Context, options *api.OptionsS) ([]*api.Schedule, error) {
+	return respSchedules()
+}
+
+func (f *fakeClient) AddScheduleContext(ctx context.Context, periodId api.PeriodId) (*api.Schedule, error) {
+	return respAddSchedule()
+}
+
+func (f *fakeClient) DetailScheduleContext(ctx context.Context, scheduleId api.ScheduleId) (*api.Schedule, error) {
+	return respScheduleDetail()
+}
+
+func (f *fakeClient) LoggingTimeListContext(ctx context.Context, scheduleId api.ScheduleId, options *api.OptionsLT) ([]*api.LoggingTime, error) {
+	return respLoggingTimeList()
+}
+
+func (f *fakeClient) AddLoggingTimeContext(ctx context.Context, scheduleId api.ScheduleId, loggingTime *api.AddLoggingTime) (*api.LoggingTime, error) {
+	return respAddLoggingTime()
+}
+
+func (f *fakeClient) DetailLoggingTimeContext(ctx context.Context, scheduleId api.ScheduleId, loggingTimeId api.LoggingTimeId) (*api.LoggingTime, error) {
+	return respDetailLoggingTime()
+}
+
+func (f *fakeClient) UpdateLoggingTimeContext(ctx context.Context, scheduleId api.ScheduleId, loggingTimeId api.LoggingTimeId, loggingTime *api.EditLoggingTime) (*api.LoggingTime, error) {
+	return respUpdateLoggingTime()
+}
+
+func (f *fakeClient) DeleteLoggingTimeContext(ctx context.Context, scheduleId api.ScheduleId, loggingTimeId api.LoggingTimeId) error {
+	return respDeleteLoggingTime()
+}
+
+func (f *fakeClient) SubmitForApproveScheduleContext(ctx context.Context, scheduleId api.ScheduleId) (*api.Schedule, error) {
+	return respSubmitForApproveSchedule()
+}
+
+func (f *fakeClient) ApproveLoggingTimeContext(ctx context.Context, scheduleId api.ScheduleId, loggingTimeId api.LoggingTimeId, comment string) (*api.LoggingTime, error) {
+	return respApproveLoggingTime()
+}
+
+func (f *fakeClient) DeclineLoggingTimeContext(ctx context.Context, scheduleId api.ScheduleId, loggingTimeId api.LoggingTimeId, comment string) (*api.LoggingTime, error) {
+	return respDeclineLoggingTime()
+}
+
+func (f *fakeClient) Projects(options *api.OptionsP) ([]*api.Project, error) {
+	return respProjects()
+}
+
+func (f *fakeClient) ProjectsContext(ctx context.Context, options *api.OptionsP) ([]*api.Project, error) {
+	return respProjects()
+}
+
+func (f *fakeClient) WorkKinds(options *api.OptionsWK) ([]*api.WorkKind, error) {
+	return respWorkKinds()
+}
+
+func (f *fakeClient) WorkKindsContext(ctx context.Context, options *api.OptionsWK) ([]*api.WorkKind, error) {
+	return respWorkKinds()
+}
+
+func (f *fakeClient) Periods(options *api.OptionsPD) ([]*api.Period, error) {
+	return respPeriods()
+}
+
+func (f *fakeClient) PeriodsContext(ctx context.Context, options *api.OptionsPD) ([]*api.Period, error) {
+	return respPeriods()
+}
 
 func SuccessRespSchedules() ([]*api.Schedule, error) {
 	return []*api.Schedule{&fakeSchedule1, &fakeSchedule2}, nil
@@ -455,6 +1448,12 @@ func SuccessRespDetailSchedule() (*api.Schedule, error) {
 	return &fakeSchedule1, nil
 }
 
+func ToApproveRespDetailSchedule() (*api.Schedule, error) {
+	schedule := fakeSchedule1
+	schedule.StatusCode = api.ToApprove
+	return &schedule, nil
+}
+
 func ErrorRespDetailSchedule() (*api.Schedule, error) {
 	return nil, errors.New("error from scheduleDetail method")
 }
@@ -471,6 +1470,19 @@ func SuccessRespLoggingTimeList() ([]*api.LoggingTime, error) {
 	return []*api.LoggingTime{&fakeLoggingTime1, &fakeLoggingTime2}, nil
 }
 
+// первый вызов возвращает временные затраты, последующие - пустой список,
+// как для исходного и нового расписания при копировании недели
+func SourceOnlyRespLoggingTimeList() loggingTimeListFunc {
+	calls := 0
+	return func() ([]*api.LoggingTime, error) {
+		calls++
+		if calls > 1 {
+			return []*api.LoggingTime{}, nil
+		}
+		return SuccessRespLoggingTimeList()
+	}
+}
+
 func ErrorRespLoggingTimeList() ([]*api.LoggingTime, error) {
 	return nil, errors.New("error from LoggingTimeList method")
 }
@@ -479,6 +1491,19 @@ func SuccessRespDetailLoggingTime() (*api.LoggingTime, error) {
 	return &fakeLoggingTime1, nil
 }
 
+func CreatedRespDetailLoggingTime() (*api.LoggingTime, error) {
+	loggingTime := fakeLoggingTime1
+	loggingTime.StatusCode = api.Created
+	return &loggingTime, nil
+}
+
+func ValidRespDetailLoggingTime() (*api.LoggingTime, error) {
+	loggingTime := fakeLoggingTime1
+	loggingTime.ProjectId = 1
+	loggingTime.WorkKindId = 2
+	return &loggingTime, nil
+}
+
 func ErrorRespDetailLoggingTime() (*api.LoggingTime, error) {
 	return nil, errors.New("error from DeleteLoggingTime method")
 }
@@ -522,3 +1547,43 @@ func SuccessRespAddLoggingTime() (*api.LoggingTime, error) {
 func ErrorRespAddLoggingTime() (*api.LoggingTime, error) {
 	return nil, errors.New("error from AddLoggingTime method")
 }
+
+func SuccessRespUpdateLoggingTime() (*api.LoggingTime, error) {
+	return &fakeLoggingTime1, nil
+}
+
+func ErrorRespUpdateLoggingTime() (*api.LoggingTime, error) {
+	return nil, errors.New("error from UpdateLoggingTime method")
+}
+
+func SuccessRespProjects() ([]*api.Project, error) {
+	return []*api.Project{{Id: 69753, Code: "SUFT", Name: "СУФТ"}}, nil
+}
+
+func ErrorRespProjects() ([]*api.Project, error) {
+	return nil, errors.New("error from Projects method")
+}
+
+func SuccessRespWorkKinds() ([]*api.WorkKind, error) {
+	return []*api.WorkKind{{Id: 21, Name: "Разработка"}}, nil
+}
+
+func ErrorRespWorkKinds() ([]*api.WorkKind, error) {
+	return nil, errors.New("error from WorkKinds method")
+}
+
+func SuccessRespPeriods() ([]*api.Period, error) {
+	return []*api.Period{&fakeSchedule1.Period}, nil
+}
+
+func ErrorRespPeriods() ([]*api.Period, error) {
+	return nil, errors.New("error from Periods method")
+}
+
+func SuccessRespWeekPeriods() ([]*api.Period, error) {
+	return []*api.Period{{Id: 5, WeekNumber: 7, StartDate: api.NewDate(2021, 2, 15), EndDate: api.NewDate(2021, 2, 21)}}, nil
+}
+
+func EmptyRespSchedules() ([]*api.Schedule, error) {
+	return []*api.Schedule{}, nil
+}
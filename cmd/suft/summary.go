@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"suftsdk/pkg/api"
+	"text/tabwriter"
+	"time"
+)
+
+var weekdayNames = map[time.Weekday]string{
+	time.Monday:    "Пн",
+	time.Tuesday:   "Вт",
+	time.Wednesday: "Ср",
+	time.Thursday:  "Чт",
+	time.Friday:    "Пт",
+	time.Saturday:  "Сб",
+	time.Sunday:    "Вс",
+}
+
+// выводит сводку по расписанию в виде таблиц
+func printSummary(out io.Writer, schedule *api.Schedule, summary *api.ScheduleSummary) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Расписание %d, неделя %d", schedule.Id, schedule.Period.WeekNumber)
+	if !schedule.Period.StartDate.IsZero() {
+		fmt.Fprintf(w, " (%s - %s)", schedule.Period.StartDate, schedule.Period.EndDate)
+	}
+	fmt.Fprintf(w, ", статус: %s\n\n", schedule.StatusCode.LabelRu())
+
+	fmt.Fprintln(w, "День\tДата\tЧасы")
+	for _, day := range summary.Days {
+		fmt.Fprintf(w, "%s\t%s\t%g\n", weekdayNames[day.Weekday], day.Date, day.Hours)
+	}
+	fmt.Fprintf(w, "Итого\t\t%g из %g\n\n", summary.Total, summary.Norm)
+
+	fmt.Fprintln(w, "Проект\tЧасы")
+	for _, id := range sortedKeys(summary.ByProject) {
+		fmt.Fprintf(w, "%d\t%g\n", id, summary.ByProject[id])
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Вид работ\tЧасы")
+	for _, id := range sortedKeys(summary.ByWorkKind) {
+		fmt.Fprintf(w, "%d\t%g\n", id, summary.ByWorkKind[id])
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Статус\tЧасы")
+	statuses := make([]string, 0, len(summary.ByStatus))
+	for status := range summary.ByStatus {
+		statuses = append(statuses, string(status))
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		statusCode := api.StatusCode(status)
+		fmt.Fprintf(w, "%s\t%g\n", statusCode.LabelRu(), summary.ByStatus[statusCode])
+	}
+	fmt.Fprintln(w)
+	if summary.Complete() {
+		fmt.Fprintln(w, "Норма часов за неделю выполнена")
+	} else {
+		fmt.Fprintf(w, "До нормы не хватает %g ч.\n", summary.Missing())
+	}
+	return w.Flush()
+}
+
+func sortedKeys(m map[int]float64) []int {
+	keys := make([]int, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	return keys
+}
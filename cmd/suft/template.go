@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"suftsdk/internal/clifuncs"
+	"suftsdk/pkg/api"
+
+	"github.com/urfave/cli"
+)
+
+func templateName(c *cli.Context) (string, error) {
+	name := c.Args().First()
+	if name == "" {
+		return "", errors.New("не передано имя шаблона")
+	}
+	return name, nil
+}
+
+func saveTemplate(c *cli.Context) error {
+	name, err := templateName(c)
+	if err != nil {
+		return err
+	}
+	template, err := clifuncs.TemplateByName(name)
+	if errors.Is(err, clifuncs.ErrTemplateNotFound) {
+		template = &clifuncs.Template{Name: name}
+	} else if err != nil {
+		return err
+	}
+	path, err := clifuncs.GenTemplateFile(template)
+	if err != nil {
+		return err
+	}
+	err = editUntilValid(path, func() (validator, error) {
+		template, err = clifuncs.TemplateFromFile(hoursParser)
+		if err == nil {
+			// шаблон сохраняется под именем из аргумента, даже если имя изменили в редакторе
+			template.Name = name
+		}
+		return template, err
+	})
+	if err != nil {
+		return err
+	}
+	err = clifuncs.SaveTemplate(template)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stderr, "Шаблон %q сохранён\n", template.Name)
+	return nil
+}
+
+func listTemplates(c *cli.Context) error {
+	templates, err := clifuncs.Templates()
+	if err != nil {
+		return err
+	}
+	return printTemplates(templates)
+}
+
+func showTemplate(c *cli.Context) error {
+	name, err := templateName(c)
+	if err != nil {
+		return err
+	}
+	template, err := clifuncs.TemplateByName(name)
+	if err != nil {
+		return err
+	}
+	return printer.PrintOne(template, templateColumns)
+}
+
+func deleteTemplate(c *cli.Context) error {
+	name, err := templateName(c)
+	if err != nil {
+		return err
+	}
+	err = clifuncs.DeleteTemplate(name)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stderr, "Шаблон %q удалён\n", name)
+	return nil
+}
+
+func applyTemplate(c *cli.Context) error {
+	name, err := templateName(c)
+	if err != nil {
+		return err
+	}
+	template, err := clifuncs.TemplateByName(name)
+	if err != nil {
+		return err
+	}
+	client, err := clientConstructor.NewClient()
+	if err != nil {
+		return err
+	}
+	scheduleId := api.ScheduleId(scheduleId)
+	err = checkScheduleStatus(client, scheduleId, api.ActionEdit)
+	if err != nil {
+		return err
+	}
+	path, err := clifuncs.GenAddLoggingTimeFile(template.NewAddLoggingTime())
+	if err != nil {
+		return err
+	}
+	return editAndAddLoggingTime(client, scheduleId, path)
+}
@@ -2,12 +2,15 @@ package auth
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
+	"strings"
 	"time"
+
+	"suftsdk/pkg/logging"
 )
 
 const (
@@ -16,61 +19,128 @@ const (
 	RefreshURL = "security/refresh-token"
 )
 
+var (
+	// ошибка, возвращаемая Refresh, когда сервер отказал в обновлении токенов
+	ErrRefresh = errors.New("unable to refresh tokens. Please re-login")
+	// неверный логин или пароль, с ней сравнивается AuthError со статусом 401.
+	// Это же значение - api.ErrUnauthorized
+	ErrUnauthorized = errors.New("unauthorized")
+)
+
+// ошибка, возвращаемая Authenticate при ответе сервера с неуспешным статусом
+type AuthError struct {
+	StatusCode int
+	// тело ответа без изменений
+	Body []byte
+}
+
+func (e *AuthError) Error() string {
+	message := strings.TrimSpace(string(e.Body))
+	if message == "" {
+		message = http.StatusText(e.StatusCode)
+	}
+	return fmt.Sprintf("%s %s: %d %s", http.MethodPost, AuthURL, e.StatusCode, message)
+}
+
+func (e *AuthError) Unwrap() error {
+	if e.StatusCode == http.StatusUnauthorized {
+		return ErrUnauthorized
+	}
+	return nil
+}
+
 type Token struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
 }
 
+type HttpClient interface {
+	Do(r *http.Request) (*http.Response, error)
+}
+
 type Options struct {
 	SuftAPIURL  string
 	HttpTimeout time.Duration
+	// HTTP-клиент для запросов, если не задан - создаётся клиент с таймаутом HttpTimeout
+	HttpClient HttpClient
+	// журнал ошибок, если не задан - записи не пишутся
+	Logger logging.Logger
 }
 
-func Authenticate(email string, password string, options *Options) (*Token, error) {
-	baseURL := BaseURL
+func (o *Options) baseURL() string {
+	if o == nil || o.SuftAPIURL == "" {
+		return BaseURL
+	}
+	if !strings.HasSuffix(o.SuftAPIURL, "/") {
+		return o.SuftAPIURL + "/"
+	}
+	return o.SuftAPIURL
+}
+
+func (o *Options) logError(httpMethod string, URN string, msg string, err error) {
+	if o == nil || o.Logger == nil {
+		return
+	}
+	o.Logger.Log(logging.Error, msg,
+		logging.Field{Key: logging.FieldMethod, Value: httpMethod},
+		logging.Field{Key: logging.FieldURN, Value: URN},
+		logging.Field{Key: logging.FieldError, Value: err},
+	)
+}
+
+func (o *Options) httpClient() HttpClient {
 	httpTimeout := 2 * time.Second
-	if options != nil {
-		if options.SuftAPIURL != "" {
-			baseURL = options.SuftAPIURL
+	if o != nil {
+		if o.HttpClient != nil {
+			return o.HttpClient
 		}
-		if options.HttpTimeout != 0 {
-			httpTimeout = options.HttpTimeout
+		if o.HttpTimeout != 0 {
+			httpTimeout = o.HttpTimeout
 		}
 	}
-
-	cli := &http.Client{
+	return &http.Client{
 		Timeout: httpTimeout,
 	}
+}
+
+func Authenticate(email string, password string, options *Options) (*Token, error) {
+	return AuthenticateContext(context.Background(), email, password, options)
+}
+
+func AuthenticateContext(ctx context.Context, email string, password string, options *Options) (*Token, error) {
+	baseURL := options.baseURL()
+	cli := options.httpClient()
 	token := &Token{}
 
 	reqBody := bytes.NewBuffer([]byte(fmt.Sprintf(`{"username":"%s","password":"%s"}`, email, password)))
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodPost,
 		fmt.Sprint(baseURL, AuthURL),
 		reqBody,
 	)
 	if err != nil {
-		log.Println("Auth: unable to create new request:", err)
-		return token, err
+		options.logError(http.MethodPost, AuthURL, "Auth: unable to create new request", err)
+		return nil, err
 	}
 	req.Header.Add("Auth-method", "Password")
 	req.Header.Add("Content-Type", "application/json; charset=UTF-8")
 
 	resp, err := cli.Do(req)
 	if err != nil {
-		log.Println("Auth: unable to get http response:", err)
-		return token, err
+		options.logError(http.MethodPost, AuthURL, "Auth: unable to get http response", err)
+		return nil, err
 	}
 
 	defer resp.Body.Close()
 
 	respB, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Println("Auth: unable to read authentification tokens:", err)
+		options.logError(http.MethodPost, AuthURL, "Auth: unable to read authentification tokens", err)
 		return nil, err
 	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, errors.New(string(respB))
+		return nil, &AuthError{StatusCode: resp.StatusCode, Body: respB}
 	}
 
 	for _, cookie := range resp.Cookies() {
@@ -86,29 +156,22 @@ func Authenticate(email string, password string, options *Options) (*Token, erro
 }
 
 func Refresh(refreshToken string, options *Options) (*Token, error) {
-	baseURL := BaseURL
-	httpTimeout := 2 * time.Second
-	if options != nil {
-		if options.SuftAPIURL != "" {
-			baseURL = options.SuftAPIURL
-		}
-		if options.HttpTimeout != 0 {
-			httpTimeout = options.HttpTimeout
-		}
-	}
+	return RefreshContext(context.Background(), refreshToken, options)
+}
 
-	cli := &http.Client{
-		Timeout: httpTimeout,
-	}
+func RefreshContext(ctx context.Context, refreshToken string, options *Options) (*Token, error) {
+	baseURL := options.baseURL()
+	cli := options.httpClient()
 	token := &Token{}
 
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodPut,
 		fmt.Sprint(baseURL, RefreshURL),
 		nil,
 	)
 	if err != nil {
-		log.Println("Refresh: unable to create new request:", err)
+		options.logError(http.MethodPut, RefreshURL, "Refresh: unable to create new request", err)
 		return nil, err
 	}
 	req.Header.Add("Auth-method", "Password")
@@ -121,14 +184,14 @@ func Refresh(refreshToken string, options *Options) (*Token, error) {
 
 	resp, err := cli.Do(req)
 	if err != nil {
-		log.Println("Refresh: unable to get http response:", err)
+		options.logError(http.MethodPut, RefreshURL, "Refresh: unable to get http response", err)
 		return nil, err
 	}
 
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, errors.New("unable to refresh tokens. Please re-login")
+		return nil, ErrRefresh
 	}
 
 	for _, cookie := range resp.Cookies() {
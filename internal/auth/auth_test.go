@@ -1,38 +1,126 @@
-package auth
+package auth_test
 
 import (
-	"fmt"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
 	"testing"
+	"time"
+
+	"suftsdk/internal/auth"
+	"suftsdk/pkg/sufttest"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/stretchr/testify/require"
 )
 
+// сервер с сотрудником demo@example.com, пароль demo
+func newServer(t *testing.T, options *sufttest.Options) (*sufttest.Server, *auth.Options) {
+	server := sufttest.NewServer(options)
+	t.Cleanup(server.Close)
+	server.AddUser("demo@example.com", "demo", 0)
+	return server, &auth.Options{SuftAPIURL: server.URL()}
+}
+
 func TestAuthenticateSuccess(t *testing.T) {
-	token, err := Authenticate("demo@example.com", "demo", nil)
+	_, options := newServer(t, nil)
+	token, err := auth.Authenticate("demo@example.com", "demo", options)
 	require.NoError(t, err)
-	fmt.Println("Access-token:", token.AccessToken)
-	fmt.Println("Refresh-token:", token.RefreshToken)
+	assert.NotEmpty(t, token.AccessToken)
+	assert.NotEmpty(t, token.RefreshToken)
 }
 
 func TestAuthenticateUnauthorized(t *testing.T) {
-	token, err := Authenticate("fake@example.com", "fake", nil)
+	_, options := newServer(t, nil)
+	token, err := auth.Authenticate("fake@example.com", "fake", options)
 	require.Error(t, err)
 	assert.Nil(t, token)
+	token, err = auth.Authenticate("demo@example.com", "fake", options)
+	require.ErrorIs(t, err, auth.ErrUnauthorized)
+	assert.Nil(t, token)
+	authErr := &auth.AuthError{}
+	require.ErrorAs(t, err, &authErr)
+	assert.Equal(t, http.StatusUnauthorized, authErr.StatusCode)
+	assert.Contains(t, string(authErr.Body), "Bad credentials")
 }
 
 func TestRefresh(t *testing.T) {
-	token, err := Authenticate("demo@example.com", "demo", nil)
+	_, options := newServer(t, nil)
+	token, err := auth.Authenticate("demo@example.com", "demo", options)
 	require.NoError(t, err)
-	tokenResp, err := Refresh(token.RefreshToken, nil)
+	tokenResp, err := auth.Refresh(token.RefreshToken, options)
 	require.NoError(t, err)
-	fmt.Println("Access-token:", tokenResp.AccessToken)
-	fmt.Println("Refresh-token:", tokenResp.RefreshToken)
+	assert.NotEmpty(t, tokenResp.AccessToken)
+	assert.NotEqual(t, token.AccessToken, tokenResp.AccessToken)
+	assert.NotEqual(t, token.RefreshToken, tokenResp.RefreshToken)
 }
 
 func TestRefreshFail(t *testing.T) {
-	token, err := Refresh("fake", nil)
-	require.Error(t, err)
+	_, options := newServer(t, nil)
+	token, err := auth.Refresh("fake", options)
+	require.ErrorIs(t, err, auth.ErrRefresh)
+	assert.Nil(t, token)
+}
+
+func TestRefreshExpired(t *testing.T) {
+	server, options := newServer(t, &sufttest.Options{RefreshTokenTTL: time.Hour})
+	token, err := auth.Authenticate("demo@example.com", "demo", options)
+	require.NoError(t, err)
+	server.Advance(2 * time.Hour)
+	tokenResp, err := auth.Refresh(token.RefreshToken, options)
+	require.ErrorIs(t, err, auth.ErrRefresh)
+	assert.Nil(t, tokenResp)
+}
+
+func TestAuthenticateContextCanceled(t *testing.T) {
+	_, options := newServer(t, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	token, err := auth.AuthenticateContext(ctx, "demo@example.com", "demo", options)
+	require.ErrorIs(t, err, context.Canceled)
 	assert.Nil(t, token)
 }
+
+type mockedHttpClient struct {
+	requests []*http.Request
+}
+
+func (m *mockedHttpClient) Do(req *http.Request) (*http.Response, error) {
+	m.requests = append(m.requests, req)
+	header := http.Header{}
+	header.Add("Set-Cookie", "Access-token=access")
+	header.Add("Set-Cookie", "Refresh-token=refresh")
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     header,
+		Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+	}, nil
+}
+func TestAuthenticateOptions(t *testing.T) {
+	httpClient := &mockedHttpClient{}
+	token, err := auth.Authenticate("demo@example.com", "demo", &auth.Options{
+		SuftAPIURL: "http://staging.example/suft",
+		HttpClient: httpClient,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &auth.Token{AccessToken: "access", RefreshToken: "refresh"}, token)
+	require.Len(t, httpClient.requests, 1)
+	assert.Equal(t, "http://staging.example/suft/security/authenticate", httpClient.requests[0].URL.String())
+}
+
+func TestRefreshOptions(t *testing.T) {
+	httpClient := &mockedHttpClient{}
+	token, err := auth.Refresh("old_refresh", &auth.Options{
+		SuftAPIURL: "http://staging.example/suft/",
+		HttpClient: httpClient,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &auth.Token{AccessToken: "access", RefreshToken: "refresh"}, token)
+	require.Len(t, httpClient.requests, 1)
+	assert.Equal(t, "http://staging.example/suft/security/refresh-token", httpClient.requests[0].URL.String())
+	cookie, err := httpClient.requests[0].Cookie("Refresh-token")
+	require.NoError(t, err)
+	assert.Equal(t, "old_refresh", cookie.Value)
+}
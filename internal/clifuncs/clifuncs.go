@@ -2,19 +2,24 @@ package clifuncs
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"golang.org/x/term"
+	"io"
 	"io/fs"
 	"io/ioutil"
-	"log"
+	"math"
 	"net/http"
 	"os"
 	"path"
+	"regexp"
+	"strconv"
 	"strings"
 	"suftsdk/internal/auth"
 	"suftsdk/pkg/api"
+	"suftsdk/pkg/logging"
 	"syscall"
 	"time"
 )
@@ -22,6 +27,18 @@ import (
 const configFileName string = "suft_config.json"
 const configDirName string = "suft"
 const loggingTimeFileName string = "logging_time.json"
+const commentPrefix string = "//"
+
+// ErrEmptyFile возвращается, если в файле временной затраты не осталось ничего, кроме комментариев
+var ErrEmptyFile = errors.New("файл временной затраты пуст")
+
+// подсказка в начале файла временной затраты
+const hoursHint string = `Часы по дням можно указать числом (7.5) или строкой: "7:30", "7h30m", "450m"`
+
+var dayTimeFields = [...]string{"day1Time", "day2Time", "day3Time", "day4Time", "day5Time", "day6Time", "day7Time"}
+
+// ErrNoInput возвращается, если во входных данных нет ни одной временной затраты
+var ErrNoInput = errors.New("не передано ни одной временной затраты")
 
 type ClientBuilder interface {
 	NewClient() (client api.API, err error)
@@ -32,10 +49,32 @@ type userConfig struct {
 	DateRefresh time.Time
 }
 
-type ClientInit struct{}
+type ClientInit struct {
+	// HTTP-клиент для запросов к API, если не задан - http.Client с таймаутом в минуту
+	HttpClient api.HttpClient
+	// журнал запросов, если не задан - записи не пишутся
+	Logger logging.Logger
+	// middleware для запросов к API
+	Middlewares []api.Middleware
+}
+
+// AuthOptions возвращает настройки входа и обновления токенов с теми же
+// HTTP-клиентом, журналом и middleware, что и у клиента API
+func (c *ClientInit) AuthOptions() *auth.Options {
+	httpClient := c.HttpClient
+	if httpClient == nil {
+		httpClient = &http.Client{
+			Timeout: time.Minute,
+		}
+	}
+	return &auth.Options{
+		HttpClient: api.Chain(httpClient, c.Middlewares...),
+		Logger:     c.Logger,
+	}
+}
 
 func (c *ClientInit) NewClient() (client api.API, err error) {
-	err = RefreshConfig()
+	err = RefreshConfig(c.AuthOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -43,6 +82,12 @@ func (c *ClientInit) NewClient() (client api.API, err error) {
 	if err != nil {
 		return nil, err
 	}
+	apiClient := client.(*api.Client)
+	if c.HttpClient != nil {
+		apiClient.HttpClient = c.HttpClient
+	}
+	apiClient.Logger = c.Logger
+	apiClient.Middlewares = c.Middlewares
 	return client, nil
 }
 
@@ -54,12 +99,12 @@ func NewClientFromConfig() (client api.API, err error) {
 	configPath, _ := configPath()
 	data, err := ioutil.ReadFile(configPath)
 	if err != nil {
-		log.Fatalln(err)
+		return nil, err
 	}
 	userConf := userConfig{}
 	err = json.Unmarshal(data, &userConf)
 	if err != nil {
-		log.Fatalln(err)
+		return nil, fmt.Errorf("не удалось прочитать конфигурацию %s: %w", configPath, err)
 	}
 	client = &api.Client{
 		BaseURL:      api.BaseURL,
@@ -68,11 +113,13 @@ func NewClientFromConfig() (client api.API, err error) {
 		HttpClient: &http.Client{
 			Timeout: time.Minute,
 		},
+		RetryPolicy: api.DefaultRetryPolicy(),
 	}
 	return client, nil
 }
 
-func LoginSuft() error {
+// LoginSuft запрашивает логин и пароль и сохраняет токены в конфигурацию
+func LoginSuft(options *auth.Options) error {
 	reader := bufio.NewReader(os.Stdin)
 	_, _ = os.Stdout.Write([]byte("Введите логин пользователя системы СУФТ:\n"))
 	login, _ := reader.ReadString('\n')
@@ -83,9 +130,9 @@ func LoginSuft() error {
 	password := string(bytePassword)
 	password = strings.Trim(password, "\n")
 
-	token, err := auth.Authenticate(login, password, nil)
+	token, err := auth.Authenticate(login, password, options)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 	err = writeConfig(token)
 	if err != nil {
@@ -151,7 +198,8 @@ func configPath() (pathConfig string, err error) {
 
 }
 
-func RefreshConfig() error {
+// RefreshConfig обновляет токены в конфигурации, если они сохранены больше двух минут назад
+func RefreshConfig(options *auth.Options) error {
 	_, err := configExists()
 	if err != nil {
 		return errors.New("не инициализирован клиент, выполните команду login")
@@ -169,7 +217,7 @@ func RefreshConfig() error {
 	if userConf.DateRefresh.Add(time.Minute * 2).After(time.Now()) {
 		return nil
 	}
-	token, err := auth.Refresh(userConf.Token.RefreshToken, nil)
+	token, err := auth.Refresh(userConf.Token.RefreshToken, options)
 	if err != nil {
 		return errors.New("время сессии истекло, пройдите аутентификацию, выполнив команду login")
 	}
@@ -193,12 +241,34 @@ func configExists() (bool, error) {
 }
 
 func GenLoggingTimeFile() (path string, err error) {
+	loggingTime := api.AddLoggingTime{
+		CommentEmployee: "",
+		Day1Time:        0,
+		Day2Time:        0,
+		Day3Time:        0,
+		Day4Time:        0,
+		Day5Time:        0,
+		Day6Time:        0,
+		Day7Time:        0,
+		ProjectId:       0,
+		Task:            "",
+		WorkKindId:      0,
+	}
+	return writeLoggingTimeFile(loggingTime)
+}
+
+// GenEditLoggingTimeFile создаёт файл для редактирования, заполненный переданными значениями
+func GenEditLoggingTimeFile(loggingTime *api.EditLoggingTime) (path string, err error) {
+	return writeLoggingTimeFile(loggingTime)
+}
+
+func writeLoggingTimeFile(loggingTime interface{}) (path string, err error) {
 	var output *os.File
 	filePath, err := loggingTimeFilePath()
-	fmt.Println(filePath)
 	if err != nil {
 		return "", err
 	}
+	fmt.Fprintln(os.Stderr, filePath)
 	_, err = os.Stat(filePath)
 	if err != nil {
 		output, err = os.Create(filePath)
@@ -212,27 +282,38 @@ func GenLoggingTimeFile() (path string, err error) {
 		}
 	}
 	defer output.Close()
-	jsonEncoder := json.NewEncoder(output)
-	loggingTime := api.AddLoggingTime{
-		CommentEmployee: "",
-		Day1Time:        0,
-		Day2Time:        0,
-		Day3Time:        0,
-		Day4Time:        0,
-		Day5Time:        0,
-		Day6Time:        0,
-		Day7Time:        0,
-		ProjectId:       0,
-		Task:            "",
-		WorkKindId:      0,
+	_, err = fmt.Fprintf(output, "%s %s\n", commentPrefix, hoursHint)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(loggingTime, "", "  ")
+	if err != nil {
+		return "", err
 	}
-	err = jsonEncoder.Encode(loggingTime)
+	_, err = fmt.Fprintf(output, "%s\n", formatHours(data))
 	if err != nil {
 		return "", err
 	}
 	return filePath, nil
 }
 
+// строка с часами за день в JSON, записанном json.MarshalIndent
+var dayTimeLine = regexp.MustCompile(`(?m)^(\s*"day[1-7]Time": )([^,\n]+)`)
+
+// записывает часы по дням в виде, который разбирает api.HoursParser, например "7h30m".
+// Часы, которые не выражаются целым числом минут, остаются числом
+func formatHours(data []byte) []byte {
+	return dayTimeLine.ReplaceAllFunc(data, func(line []byte) []byte {
+		match := dayTimeLine.FindSubmatch(line)
+		hours, err := strconv.ParseFloat(string(match[2]), 64)
+		minutes := hours * 60
+		if err != nil || hours < 0 || math.Abs(minutes-math.Round(minutes)) > 1e-9 {
+			return line
+		}
+		return append(match[1], strconv.Quote(api.FormatHours(hours))...)
+	})
+}
+
 func loggingTimeFilePath() (filePath string, err error) {
 	configDir, err := os.UserConfigDir()
 	if err != nil {
@@ -243,7 +324,7 @@ func loggingTimeFilePath() (filePath string, err error) {
 
 }
 
-func LoggingTimeFromFile() (loggingTime *api.AddLoggingTime, err error) {
+func LoggingTimeFromFile(parser api.HoursParser) (loggingTime *api.AddLoggingTime, err error) {
 	path, err := loggingTimeFilePath()
 	if err != nil {
 		return nil, err
@@ -252,14 +333,180 @@ func LoggingTimeFromFile() (loggingTime *api.AddLoggingTime, err error) {
 	if err != nil {
 		return nil, err
 	}
-	data, err := ioutil.ReadFile(path)
+	data, err := readLoggingTimeFile(path, parser)
 	if err != nil {
-		log.Fatalln(err)
+		return nil, err
 	}
 	logTime := api.AddLoggingTime{}
 	err = json.Unmarshal(data, &logTime)
 	if err != nil {
-		log.Fatalln(err)
+		return nil, err
 	}
 	return &logTime, nil
 }
+
+// LoggingTimesFromReader читает временные затраты из JSON: один объект,
+// массив объектов или поток объектов NDJSON. Часы разбираются parser
+func LoggingTimesFromReader(r io.Reader, parser api.HoursParser) ([]*api.AddLoggingTime, error) {
+	reader := bufio.NewReader(r)
+	first, err := firstNonSpace(reader)
+	if err == io.EOF {
+		return nil, ErrNoInput
+	}
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(reader)
+	loggingTimes := []*api.AddLoggingTime{}
+	objects := []json.RawMessage{}
+	if first == '[' {
+		err = decoder.Decode(&objects)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		for {
+			var object json.RawMessage
+			err = decoder.Decode(&object)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("объект %d: %w", len(objects)+1, err)
+			}
+			objects = append(objects, object)
+		}
+	}
+	for i, object := range objects {
+		data, err := normalizeHours(object, parser)
+		if err != nil {
+			return nil, fmt.Errorf("объект %d: %w", i+1, err)
+		}
+		loggingTime := &api.AddLoggingTime{}
+		err = json.Unmarshal(data, loggingTime)
+		if err != nil {
+			return nil, fmt.Errorf("объект %d: %w", i+1, err)
+		}
+		loggingTimes = append(loggingTimes, loggingTime)
+	}
+	if len(loggingTimes) == 0 {
+		return nil, ErrNoInput
+	}
+	return loggingTimes, nil
+}
+
+// возвращает первый непробельный символ, не извлекая его из reader
+func firstNonSpace(reader *bufio.Reader) (byte, error) {
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if !strings.ContainsRune(" \t\r\n", rune(b)) {
+			return b, reader.UnreadByte()
+		}
+	}
+}
+
+func EditLoggingTimeFromFile(parser api.HoursParser) (loggingTime *api.EditLoggingTime, err error) {
+	path, err := loggingTimeFilePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := readLoggingTimeFile(path, parser)
+	if err != nil {
+		return nil, err
+	}
+	editLoggingTime := api.EditLoggingTime{}
+	err = json.Unmarshal(data, &editLoggingTime)
+	if err != nil {
+		return nil, err
+	}
+	return &editLoggingTime, nil
+}
+
+// AnnotateLoggingTimeFile записывает сообщения комментариями в начало файла
+// временной затраты, заменяя комментарии, добавленные ранее
+func AnnotateLoggingTimeFile(messages []string) error {
+	path, err := loggingTimeFilePath()
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var annotated bytes.Buffer
+	for _, message := range messages {
+		fmt.Fprintf(&annotated, "%s %s\n", commentPrefix, message)
+	}
+	annotated.Write(stripComments(data))
+	return ioutil.WriteFile(path, annotated.Bytes(), 0644)
+}
+
+// читает файл временной затраты без строк комментариев
+func readLoggingTimeFile(path string, parser api.HoursParser) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data = stripComments(data)
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, ErrEmptyFile
+	}
+	return normalizeHours(data, parser)
+}
+
+// заменяет часы, записанные строками, например "7:30", числами
+// и округляет все часы по parser
+func normalizeHours(data []byte, parser api.HoursParser) ([]byte, error) {
+	fields := map[string]json.RawMessage{}
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range dayTimeFields {
+		raw, ok := fields[name]
+		if !ok {
+			continue
+		}
+		var value interface{}
+		err = json.Unmarshal(raw, &value)
+		if err != nil {
+			return nil, err
+		}
+		var hours float64
+		switch v := value.(type) {
+		case string:
+			hours, err = parser.Parse(v)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+		case float64:
+			hours = parser.Round(v)
+		default:
+			continue
+		}
+		fields[name], err = json.Marshal(hours)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(fields)
+}
+
+func stripComments(data []byte) []byte {
+	var stripped bytes.Buffer
+	for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+		if bytes.HasPrefix(bytes.TrimSpace(line), []byte(commentPrefix)) {
+			continue
+		}
+		stripped.Write(line)
+	}
+	return stripped.Bytes()
+}
+
+// GenAddLoggingTimeFile создаёт файл для редактирования, заполненный переданными значениями
+func GenAddLoggingTimeFile(loggingTime *api.AddLoggingTime) (path string, err error) {
+	return writeLoggingTimeFile(loggingTime)
+}
@@ -0,0 +1,163 @@
+package clifuncs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"suftsdk/pkg/api"
+)
+
+const templatesFileName string = "templates.json"
+
+// ErrTemplateNotFound возвращается, если шаблона с таким именем нет
+var ErrTemplateNotFound = errors.New("шаблон не найден")
+
+// Template - именованный шаблон временной затраты
+type Template struct {
+	Name string `json:"name"`
+	api.AddLoggingTime
+}
+
+// ValidateWith проверяет шаблон по переданным правилам. Часы в шаблоне можно не указывать
+func (t *Template) ValidateWith(rules api.ValidationRules) error {
+	rules.RequireHours = false
+	err := t.AddLoggingTime.ValidateWith(rules)
+	if strings.TrimSpace(t.Name) != "" {
+		return err
+	}
+	nameErr := api.FieldError{Field: "name", Code: api.CodeRequired}
+	validationErr := &api.ValidationError{}
+	if errors.As(err, &validationErr) {
+		validationErr.Errors = append([]api.FieldError{nameErr}, validationErr.Errors...)
+		return validationErr
+	}
+	return &api.ValidationError{Errors: []api.FieldError{nameErr}}
+}
+
+// NewAddLoggingTime возвращает временную затрату, заполненную по шаблону
+func (t *Template) NewAddLoggingTime() *api.AddLoggingTime {
+	loggingTime := t.AddLoggingTime
+	return &loggingTime
+}
+
+// Templates возвращает сохранённые шаблоны, упорядоченные по имени
+func Templates() ([]*Template, error) {
+	filePath, err := templatesFilePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []*Template{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	templates := []*Template{}
+	err = json.Unmarshal(data, &templates)
+	if err != nil {
+		return nil, fmt.Errorf("файл шаблонов %s повреждён: %w", filePath, err)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return templates, nil
+}
+
+// TemplateByName возвращает шаблон по имени
+func TemplateByName(name string) (*Template, error) {
+	templates, err := Templates()
+	if err != nil {
+		return nil, err
+	}
+	for _, template := range templates {
+		if template.Name == name {
+			return template, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+}
+
+// SaveTemplate сохраняет шаблон, заменяя шаблон с тем же именем
+func SaveTemplate(template *Template) error {
+	templates, err := Templates()
+	if err != nil {
+		return err
+	}
+	saved := []*Template{template}
+	for _, t := range templates {
+		if t.Name != template.Name {
+			saved = append(saved, t)
+		}
+	}
+	return writeTemplates(saved)
+}
+
+// DeleteTemplate удаляет шаблон по имени
+func DeleteTemplate(name string) error {
+	templates, err := Templates()
+	if err != nil {
+		return err
+	}
+	kept := []*Template{}
+	for _, template := range templates {
+		if template.Name != name {
+			kept = append(kept, template)
+		}
+	}
+	if len(kept) == len(templates) {
+		return fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+	return writeTemplates(kept)
+}
+
+// GenTemplateFile создаёт файл для редактирования шаблона
+func GenTemplateFile(template *Template) (path string, err error) {
+	return writeLoggingTimeFile(template)
+}
+
+// TemplateFromFile читает шаблон из файла для редактирования
+func TemplateFromFile(parser api.HoursParser) (template *Template, err error) {
+	path, err := loggingTimeFilePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := readLoggingTimeFile(path, parser)
+	if err != nil {
+		return nil, err
+	}
+	template = &Template{}
+	err = json.Unmarshal(data, template)
+	if err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func writeTemplates(templates []*Template) error {
+	filePath, err := templatesFilePath()
+	if err != nil {
+		return err
+	}
+	_ = os.MkdirAll(path.Dir(filePath), fs.ModePerm)
+	data, err := json.MarshalIndent(templates, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filePath, data, 0600)
+}
+
+func templatesFilePath() (filePath string, err error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	filePath = path.Join(configDir, configDirName, templatesFileName)
+	return filePath, nil
+}
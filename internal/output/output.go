@@ -0,0 +1,271 @@
+// Package output выводит результаты команд CLI таблицей, в JSON, YAML, NDJSON
+// или по шаблону text/template
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Format string
+
+const (
+	Table    Format = "table"
+	JSON     Format = "json"
+	YAML     Format = "yaml"
+	NDJSON   Format = "ndjson"
+	Template Format = "template"
+)
+
+// ErrUnknownFormat возвращается для неподдерживаемого формата вывода
+var ErrUnknownFormat = errors.New("unknown output format")
+
+// столбец вывода
+type Column struct {
+	// имя для выбора столбца через Fields
+	Name string
+	// заголовок таблицы
+	Title string
+	// значение столбца для элемента
+	Value func(item interface{}) interface{}
+	// выводить столбец, если Fields не заданы
+	Default bool
+}
+
+// Printer выводит элементы в выбранном формате
+type Printer struct {
+	Format Format
+	// имена выводимых столбцов; пусто - столбцы по умолчанию для таблицы
+	// и все поля элемента для JSON, YAML и NDJSON
+	Fields []string
+	// шаблон text/template для формата Template, применяется к каждому элементу
+	Template string
+	Out      io.Writer
+}
+
+// Validate проверяет формат и шаблон; имена столбцов проверяются при выводе
+func (p *Printer) Validate() error {
+	switch p.Format {
+	case Table, JSON, YAML, NDJSON:
+	case Template:
+		if p.Template == "" {
+			return errors.New("template output requires a template")
+		}
+		if _, err := template.New("output").Parse(p.Template); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownFormat, p.Format)
+	}
+	return nil
+}
+
+// Print выводит список элементов
+func (p *Printer) Print(items []interface{}, columns []Column) error {
+	switch p.Format {
+	case Table:
+		return p.printTable(items, columns)
+	case JSON:
+		values, err := p.values(items, columns)
+		if err != nil {
+			return err
+		}
+		return p.printJSON(values)
+	case YAML:
+		values, err := p.values(items, columns)
+		if err != nil {
+			return err
+		}
+		return p.printYAML(values)
+	case NDJSON:
+		values, err := p.values(items, columns)
+		if err != nil {
+			return err
+		}
+		for _, value := range values {
+			data, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(p.Out, "%s\n", data); err != nil {
+				return err
+			}
+		}
+		return nil
+	case Template:
+		return p.printTemplate(items)
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownFormat, p.Format)
+}
+
+// PrintOne выводит один элемент: в JSON и YAML объектом, а не списком
+func (p *Printer) PrintOne(item interface{}, columns []Column) error {
+	switch p.Format {
+	case JSON:
+		values, err := p.values([]interface{}{item}, columns)
+		if err != nil {
+			return err
+		}
+		return p.printJSON(values[0])
+	case YAML:
+		values, err := p.values([]interface{}{item}, columns)
+		if err != nil {
+			return err
+		}
+		return p.printYAML(values[0])
+	}
+	return p.Print([]interface{}{item}, columns)
+}
+
+func (p *Printer) selectColumns(columns []Column) ([]Column, error) {
+	if len(p.Fields) == 0 {
+		selected := []Column{}
+		for _, column := range columns {
+			if column.Default {
+				selected = append(selected, column)
+			}
+		}
+		return selected, nil
+	}
+	selected := make([]Column, 0, len(p.Fields))
+	for _, field := range p.Fields {
+		found := false
+		for _, column := range columns {
+			if strings.EqualFold(column.Name, field) {
+				selected = append(selected, column)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown field %q, available: %s", field, columnNames(columns))
+		}
+	}
+	return selected, nil
+}
+
+func columnNames(columns []Column) string {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+func (p *Printer) printTable(items []interface{}, columns []Column) error {
+	selected, err := p.selectColumns(columns)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(p.Out, 0, 0, 2, ' ', 0)
+	titles := make([]string, len(selected))
+	for i, column := range selected {
+		titles[i] = column.Title
+	}
+	fmt.Fprintln(w, strings.Join(titles, "\t"))
+	for _, item := range items {
+		cells := make([]string, len(selected))
+		for i, column := range selected {
+			cells[i] = formatValue(column.Value(item))
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+	return w.Flush()
+}
+
+// значения для JSON и YAML: элемент целиком или выбранные столбцы
+func (p *Printer) values(items []interface{}, columns []Column) ([]interface{}, error) {
+	if len(p.Fields) == 0 {
+		return items, nil
+	}
+	selected, err := p.selectColumns(columns)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, len(items))
+	for i, item := range items {
+		fields := map[string]interface{}{}
+		for _, column := range selected {
+			fields[column.Name] = column.Value(item)
+		}
+		values[i] = fields
+	}
+	return values, nil
+}
+
+func (p *Printer) printJSON(value interface{}) error {
+	encoder := json.NewEncoder(p.Out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// YAML строится из JSON, чтобы имена и порядок полей совпадали с JSON
+func (p *Printer) printYAML(value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	node := yaml.Node{}
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	clearStyle(&node)
+	encoder := yaml.NewEncoder(p.Out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// убирает стиль потока JSON, чтобы YAML выводился блоками
+func clearStyle(node *yaml.Node) {
+	node.Style = node.Style &^ yaml.FlowStyle
+	if node.Kind == yaml.ScalarNode && node.Style&yaml.DoubleQuotedStyle != 0 {
+		node.Style = 0
+	}
+	for _, child := range node.Content {
+		clearStyle(child)
+	}
+}
+
+func (p *Printer) printTemplate(items []interface{}) error {
+	tmpl, err := template.New("output").Parse(p.Template)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, item); err != nil {
+			return err
+		}
+		if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteString("\n")
+		}
+		if _, err := p.Out.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case fmt.Stringer:
+		return v.String()
+	}
+	return fmt.Sprint(value)
+}
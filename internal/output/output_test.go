@@ -0,0 +1,83 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type item struct {
+	Id     int     `json:"id"`
+	Name   string  `json:"name"`
+	Hours  float64 `json:"hours"`
+	Status string  `json:"status"`
+}
+
+var items = []interface{}{
+	&item{Id: 1, Name: "Разработка", Hours: 8, Status: "УТВ"},
+	&item{Id: 22, Name: "Тесты", Hours: 1.5, Status: "СЗ"},
+}
+
+var columns = []Column{
+	{Name: "id", Title: "ID", Default: true, Value: func(i interface{}) interface{} { return i.(*item).Id }},
+	{Name: "name", Title: "Название", Default: true, Value: func(i interface{}) interface{} { return i.(*item).Name }},
+	{Name: "hours", Title: "Часы", Default: true, Value: func(i interface{}) interface{} { return i.(*item).Hours }},
+	{Name: "status", Title: "Статус", Value: func(i interface{}) interface{} { return i.(*item).Status }},
+}
+
+func render(t *testing.T, printer Printer) string {
+	var buf bytes.Buffer
+	printer.Out = &buf
+	require.NoError(t, printer.Print(items, columns))
+	return buf.String()
+}
+
+func TestTable(t *testing.T) {
+	assert.Equal(t, "ID  Название    Часы\n"+
+		"1   Разработка  8\n"+
+		"22  Тесты       1.5\n", render(t, Printer{Format: Table}))
+}
+
+func TestTableFields(t *testing.T) {
+	assert.Equal(t, "Статус  ID\n"+
+		"УТВ     1\n"+
+		"СЗ      22\n", render(t, Printer{Format: Table, Fields: []string{"status", "ID"}}))
+}
+
+func TestUnknownField(t *testing.T) {
+	printer := Printer{Format: Table, Fields: []string{"author"}, Out: &bytes.Buffer{}}
+	err := printer.Print(items, columns)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "id, name, hours, status")
+}
+
+func TestNDJSON(t *testing.T) {
+	assert.Equal(t, `{"id":1,"name":"Разработка","hours":8,"status":"УТВ"}`+"\n"+
+		`{"id":22,"name":"Тесты","hours":1.5,"status":"СЗ"}`+"\n", render(t, Printer{Format: NDJSON}))
+}
+
+func TestJSONFields(t *testing.T) {
+	assert.Equal(t, "[\n  {\n    \"id\": 1\n  },\n  {\n    \"id\": 22\n  }\n]\n",
+		render(t, Printer{Format: JSON, Fields: []string{"id"}}))
+}
+
+func TestYAML(t *testing.T) {
+	var buf bytes.Buffer
+	printer := Printer{Format: YAML, Out: &buf}
+	require.NoError(t, printer.PrintOne(items[0], columns))
+	assert.Equal(t, "id: 1\nname: Разработка\nhours: 8\nstatus: УТВ\n", buf.String())
+}
+
+func TestTemplate(t *testing.T) {
+	assert.Equal(t, "1: Разработка\n22: Тесты\n",
+		render(t, Printer{Format: Template, Template: "{{.Id}}: {{.Name}}"}))
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, (&Printer{Format: Table}).Validate())
+	assert.ErrorIs(t, (&Printer{Format: "xml"}).Validate(), ErrUnknownFormat)
+	assert.Error(t, (&Printer{Format: Template}).Validate())
+	assert.Error(t, (&Printer{Format: Template, Template: "{{.Id"}).Validate())
+}
@@ -0,0 +1,202 @@
+// Package xlsx читает и записывает простые таблицы в формате XLSX (Office Open XML):
+// только значения ячеек первого листа, без стилей и формул
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+const (
+	workbookPath      = "xl/workbook.xml"
+	workbookRelsPath  = "xl/_rels/workbook.xml.rels"
+	sharedStringsPath = "xl/sharedStrings.xml"
+	defaultSheetPath  = "xl/worksheets/sheet1.xml"
+)
+
+// ErrNoSheet возвращается, если в книге нет листов
+var ErrNoSheet = errors.New("xlsx: workbook has no sheets")
+
+type workbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RId  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type relationships struct {
+	Relationships []struct {
+		Id     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type richText struct {
+	T string `xml:"t"`
+	R []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (r richText) text() string {
+	if len(r.R) == 0 {
+		return r.T
+	}
+	var b strings.Builder
+	for _, run := range r.R {
+		b.WriteString(run.T)
+	}
+	return b.String()
+}
+
+type sharedStrings struct {
+	Items []richText `xml:"si"`
+}
+
+type worksheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R  string   `xml:"r,attr"`
+			T  string   `xml:"t,attr"`
+			V  string   `xml:"v"`
+			Is richText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// ReadRows возвращает значения ячеек первого листа построчно.
+// Пустые строки и ячейки сохраняются, чтобы номера строк и столбцов совпадали с листом
+func ReadRows(r io.ReaderAt, size int64) ([][]string, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("xlsx: %w", err)
+	}
+	files := map[string]*zip.File{}
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+	strs := sharedStrings{}
+	if file, ok := files[sharedStringsPath]; ok {
+		if err := decodeFile(file, &strs); err != nil {
+			return nil, err
+		}
+	}
+	file, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("xlsx: sheet %s not found", sheetPath)
+	}
+	sheet := worksheet{}
+	if err := decodeFile(file, &sheet); err != nil {
+		return nil, err
+	}
+
+	rows := [][]string{}
+	for i, row := range sheet.Rows {
+		number := row.R
+		if number == 0 {
+			number = i + 1
+		}
+		for len(rows) < number {
+			rows = append(rows, []string{})
+		}
+		cells := []string{}
+		for j, cell := range row.Cells {
+			column := j
+			if cell.R != "" {
+				column, err = columnIndex(cell.R)
+				if err != nil {
+					return nil, err
+				}
+			}
+			for len(cells) <= column {
+				cells = append(cells, "")
+			}
+			switch cell.T {
+			case "s":
+				var index int
+				if _, err := fmt.Sscan(cell.V, &index); err != nil || index < 0 || index >= len(strs.Items) {
+					return nil, fmt.Errorf("xlsx: invalid shared string index %q in %s", cell.V, cell.R)
+				}
+				cells[column] = strs.Items[index].text()
+			case "inlineStr":
+				cells[column] = cell.Is.text()
+			default:
+				cells[column] = cell.V
+			}
+		}
+		rows[number-1] = cells
+	}
+	return rows, nil
+}
+
+// путь к первому листу книги, по умолчанию xl/worksheets/sheet1.xml
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	file, ok := files[workbookPath]
+	if !ok {
+		return defaultSheetPath, nil
+	}
+	book := workbook{}
+	if err := decodeFile(file, &book); err != nil {
+		return "", err
+	}
+	if len(book.Sheets) == 0 {
+		return "", ErrNoSheet
+	}
+	relsFile, ok := files[workbookRelsPath]
+	if !ok {
+		return defaultSheetPath, nil
+	}
+	rels := relationships{}
+	if err := decodeFile(relsFile, &rels); err != nil {
+		return "", err
+	}
+	for _, rel := range rels.Relationships {
+		if rel.Id != book.Sheets[0].RId {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return defaultSheetPath, nil
+}
+
+func decodeFile(file *zip.File, v interface{}) error {
+	rc, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("xlsx: %s: %w", file.Name, err)
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("xlsx: %s: %w", file.Name, err)
+	}
+	return nil
+}
+
+// columnIndex возвращает номер столбца (с нуля) по адресу ячейки, например "C7" - 2
+func columnIndex(ref string) (int, error) {
+	index := 0
+	letters := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		index = index*26 + int(r-'A'+1)
+		letters++
+	}
+	if letters == 0 {
+		return 0, fmt.Errorf("xlsx: invalid cell reference %q", ref)
+	}
+	return index - 1, nil
+}
@@ -0,0 +1,93 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func zipFiles(t *testing.T, files map[string]string) *bytes.Reader {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestReadRows(t *testing.T) {
+	r := zipFiles(t, map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+			xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+			<sheets><sheet name="Лист1" sheetId="1" r:id="rId3"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+			<Relationship Id="rId3" Type="worksheet" Target="worksheets/data.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst><si><t>task</t></si><si><r><t>Раз</t></r><r><t>работка</t></r></si></sst>`,
+		"xl/worksheets/data.xml": `<worksheet><sheetData>
+			<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="inlineStr"><is><t>day1Time</t></is></c></row>
+			<row r="3"><c r="A3" t="s"><v>1</v></c><c r="C3"><v>7.5</v></c></row>
+			</sheetData></worksheet>`,
+	})
+	rows, err := ReadRows(r, r.Size())
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"task", "", "day1Time"},
+		{},
+		{"Разработка", "", "7.5"},
+	}, rows)
+}
+
+func TestReadRowsInvalid(t *testing.T) {
+	r := bytes.NewReader([]byte("not a zip"))
+	_, err := ReadRows(r, r.Size())
+	require.Error(t, err)
+
+	r = zipFiles(t, map[string]string{
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row r="1"><c r="A1" t="s"><v>5</v></c></row></sheetData></worksheet>`,
+	})
+	_, err = ReadRows(r, r.Size())
+	require.Error(t, err)
+}
+
+func TestColumnIndex(t *testing.T) {
+	for ref, index := range map[string]int{"A1": 0, "C7": 2, "Z2": 25, "AA10": 26, "AB1": 27} {
+		got, err := columnIndex(ref)
+		require.NoError(t, err)
+		assert.Equal(t, index, got, ref)
+	}
+	_, err := columnIndex("12")
+	require.Error(t, err)
+}
+
+func TestWriteRows(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteRows(&buf, "Отчёт", [][]interface{}{
+		{"id", "task", "hours"},
+		{1, "<задача> & ко", 7.5},
+	})
+	require.NoError(t, err)
+
+	r := bytes.NewReader(buf.Bytes())
+	rows, err := ReadRows(r, r.Size())
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"id", "task", "hours"},
+		{"1", "<задача> & ко", "7.5"},
+	}, rows)
+}
+
+func TestColumnName(t *testing.T) {
+	for index, name := range map[int]string{0: "A", 2: "C", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		assert.Equal(t, name, columnName(index))
+		got, err := columnIndex(name + "1")
+		require.NoError(t, err)
+		assert.Equal(t, index, got)
+	}
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"io/ioutil"
@@ -23,10 +24,10 @@ var fakeSchedule1 = Schedule{
 	},
 	Id: 0,
 	Period: Period{
-		CloseDate:  "",
-		EndDate:    "",
+		CloseDate:  Date{},
+		EndDate:    Date{},
 		Id:         5,
-		StartDate:  "",
+		StartDate:  Date{},
 		WeekNumber: 2,
 	},
 	StatusCode: "22",
@@ -42,10 +43,10 @@ var fakeSchedule2 = Schedule{
 	},
 	Id: 0,
 	Period: Period{
-		CloseDate:  "",
-		EndDate:    "",
+		CloseDate:  Date{},
+		EndDate:    Date{},
 		Id:         5,
-		StartDate:  "",
+		StartDate:  Date{},
 		WeekNumber: 2,
 	},
 	StatusCode: "25",
@@ -99,6 +100,15 @@ func (m *mockedHttpClient) Do(req *http.Request) (*http.Response, error) {
 	return GetRequireResp()
 }
 
+type ctxHttpClient struct{}
+
+func (m *ctxHttpClient) Do(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	return GetRequireResp()
+}
+
 func TestSchedulesSuccess(t *testing.T) {
 	client, err := NewFakeClient()
 	GetRequireResp = SuccessRespSchedules
@@ -136,6 +146,32 @@ func TestSchedulesError(t *testing.T) {
 	assert.Nil(t, schedules)
 }
 
+func TestSchedulesContextCanceled(t *testing.T) {
+	client, err := NewFakeClient()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	client.HttpClient = new(ctxHttpClient)
+	GetRequireResp = SuccessRespSchedules
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	schedules, err := client.SchedulesContext(ctx, nil)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, schedules)
+}
+
+func TestSchedulesForbidden(t *testing.T) {
+	client, err := NewFakeClient()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	GetRequireResp = ForbiddenResp
+	schedules, err := client.Schedules(nil)
+	require.ErrorIs(t, err, ErrForbidden)
+	assert.NotErrorIs(t, err, ErrNotFound)
+	assert.Nil(t, schedules)
+}
+
 func TestAddScheduleSuccess(t *testing.T) {
 	client, err := NewFakeClient()
 	if err != nil {
@@ -336,6 +372,24 @@ func TestDetailLoggingTimeUnauthorized(t *testing.T) {
 	assert.Nil(t, loggingTimeResp)
 }
 
+func TestDetailLoggingTimeNotFound(t *testing.T) {
+	client, err := NewFakeClient()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	GetRequireResp = NotFoundResp
+	loggingTimeResp, err := client.DetailLoggingTime(5, 777)
+	require.ErrorIs(t, err, ErrNotFound)
+	assert.Nil(t, loggingTimeResp)
+	apiErr := &APIError{}
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, http.MethodGet, apiErr.Method)
+	assert.Equal(t, "api/v1/schedules/5/logging-times/777", apiErr.URN)
+	require.NotNil(t, apiErr.Body)
+	assert.Equal(t, "Logging time not found", apiErr.Message())
+}
+
 func TestDetailLoggingTimeError(t *testing.T) {
 	client, err := NewFakeClient()
 	if err != nil {
@@ -347,8 +401,48 @@ func TestDetailLoggingTimeError(t *testing.T) {
 	assert.Nil(t, loggingTimeResp)
 }
 
+func TestClientBaseURL(t *testing.T) {
+	client, httpClient := newRetryClient(jsonResp(http.StatusOK, fakeSchedule1))
+	client.BaseURL = "http://staging.example/suft"
+	_, err := client.DetailSchedule(777)
+	require.NoError(t, err)
+	require.Len(t, httpClient.requests, 1)
+	assert.Equal(t, "http://staging.example/suft/api/v1/schedules/777", httpClient.requests[0].URL.String())
+}
+
+func TestRefreshUsesClientOptions(t *testing.T) {
+	refreshResp := func(req *http.Request) (*http.Response, error) {
+		header := http.Header{}
+		header.Add("Set-Cookie", "Access-token=new_access_token")
+		header.Add("Set-Cookie", "Refresh-token=new_refresh_token")
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     header,
+			Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+		}, nil
+	}
+	client, httpClient := newRetryClient(
+		statusResp(http.StatusUnauthorized, "Unauthorized"),
+		refreshResp,
+		jsonResp(http.StatusOK, fakeSchedule1),
+	)
+	client.BaseURL = "http://staging.example/suft/"
+	_, err := client.DetailSchedule(777)
+	require.NoError(t, err)
+	require.Len(t, httpClient.requests, 3)
+	assert.Equal(t, "http://staging.example/suft/security/refresh-token", httpClient.requests[1].URL.String())
+	cookie, err := httpClient.requests[1].Cookie("Refresh-token")
+	require.NoError(t, err)
+	assert.Equal(t, "fake_refresh_token", cookie.Value)
+	cookie, err = httpClient.requests[2].Cookie("Access-token")
+	require.NoError(t, err)
+	assert.Equal(t, "new_access_token", cookie.Value)
+	assert.Equal(t, "new_access_token", client.AccessToken)
+	assert.Equal(t, "new_refresh_token", client.RefreshToken)
+}
+
 func NewFakeClient() (*Client, error) {
-	httpClient = new(mockedHttpClient)
+	httpClient := new(mockedHttpClient)
 	return &Client{
 		BaseURL:      BaseURL,
 		AccessToken:  "fake_access_token",
@@ -376,6 +470,22 @@ func UnauthorizedResp() (*http.Response, error) {
 	return &resp, nil
 }
 
+func ForbiddenResp() (*http.Response, error) {
+	respB := []byte("Forbidden")
+	body := ioutil.NopCloser(bytes.NewReader(respB))
+	resp := http.Response{StatusCode: http.StatusForbidden,
+		Body: body}
+	return &resp, nil
+}
+
+func NotFoundResp() (*http.Response, error) {
+	respB := []byte(`{"timestamp":"2021-09-20T10:00:00.000+0000","status":404,"error":"Not Found","message":"Logging time not found","path":"/api/v1/schedules/5/logging-times/777"}`)
+	body := ioutil.NopCloser(bytes.NewReader(respB))
+	resp := http.Response{StatusCode: http.StatusNotFound,
+		Body: body}
+	return &resp, nil
+}
+
 func ErrorRespFromDoHttp() (*http.Response, error) {
 	return nil, errors.New("error from doHTTP")
 }
@@ -439,38 +549,40 @@ func SuccessRespDetailLoggingTime() (*http.Response, error) {
 	return &resp, nil
 }
 
-// func TestEditLoggingTimeSuccess(t *testing.T) {
-// 	client, err := NewFakeClient()
-// 	if err != nil {
-// 		log.Fatalln(err)
-// 	}
-// 	GetRequireResp = SuccessRespDetailLoggingTime
-// 	loggingTimeResp, err := client.EditLoggingTime(777, 777, &loggingtime.EditLoggingTime{})
-// 	require.NoError(t, err)
-// 	assert.Equal(t, &fakeLoggingTime1, loggingTimeResp)
-// }
-
-// func TestEditLoggingTimeUnauthorized(t *testing.T) {
-// 	client, err := NewFakeClient()
-// 	if err != nil {
-// 		log.Fatalln(err)
-// 	}
-// 	GetRequireResp = UnauthorizedResp
-// 	loggingTimeResp, err := client.EditLoggingTime(777, 777, &loggingtime.EditLoggingTime{})
-// 	assert.Error(t, err)
-// 	assert.Nil(t, loggingTimeResp)
-// }
-
-// func TestEditLoggingTimeError(t *testing.T) {
-// 	client, err := NewFakeClient()
-// 	if err != nil {
-// 		log.Fatalln(err)
-// 	}
-// 	GetRequireResp = ErrorRespFromDoHttp
-// 	loggingTimeResp, err := client.EditLoggingTime(777, 777, &loggingtime.EditLoggingTime{})
-// 	require.Error(t, err)
-// 	assert.Nil(t, loggingTimeResp)
-// }
+func TestUpdateLoggingTimeSuccess(t *testing.T) {
+	client, err := NewFakeClient()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	expected := &fakeLoggingTime1
+	expected.client = client
+	GetRequireResp = SuccessRespDetailLoggingTime
+	loggingTimeResp, err := client.UpdateLoggingTime(777, 777, &EditLoggingTime{})
+	require.NoError(t, err)
+	assert.Equal(t, expected, loggingTimeResp)
+}
+
+func TestUpdateLoggingTimeUnauthorized(t *testing.T) {
+	client, err := NewFakeClient()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	GetRequireResp = UnauthorizedResp
+	loggingTimeResp, err := client.UpdateLoggingTime(777, 777, &EditLoggingTime{})
+	assert.Error(t, err)
+	assert.Nil(t, loggingTimeResp)
+}
+
+func TestUpdateLoggingTimeError(t *testing.T) {
+	client, err := NewFakeClient()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	GetRequireResp = ErrorRespFromDoHttp
+	loggingTimeResp, err := client.UpdateLoggingTime(777, 777, &EditLoggingTime{})
+	require.Error(t, err)
+	assert.Nil(t, loggingTimeResp)
+}
 
 func TestDeleteLoggingTimeSuccess(t *testing.T) {
 	client, err := NewFakeClient()
@@ -493,6 +605,19 @@ func TestDeleteLoggingTimeUnauthorized(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestDeleteLoggingTimeContextCanceled(t *testing.T) {
+	client, err := NewFakeClient()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	client.HttpClient = new(ctxHttpClient)
+	GetRequireResp = SuccessRespDeleteLoggingTime
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = client.DeleteLoggingTimeContext(ctx, 777, 777)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
 func TestDeleteLoggingTimeError(t *testing.T) {
 	client, err := NewFakeClient()
 	if err != nil {
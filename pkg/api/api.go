@@ -2,13 +2,16 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
+	"strings"
 	"suftsdk/internal/auth"
+	"suftsdk/pkg/logging"
+	"sync"
 	"time"
 )
 
@@ -28,12 +31,18 @@ type HttpClient interface {
 	Do(r *http.Request) (*http.Response, error)
 }
 
-var httpClient HttpClient
-
 // опции для функции NewClient
 type OptionsNC struct {
 	SuftAPIURL  string
 	HttpTimeout time.Duration
+	// HTTP-клиент для всех запросов, включая аутентификацию и обновление токенов.
+	// Если не задан, создаётся http.Client с таймаутом HttpTimeout
+	HttpClient  HttpClient
+	RetryPolicy *RetryPolicy
+	// журнал запросов, повторов и ошибок, если не задан - записи не пишутся
+	Logger logging.Logger
+	// цепочка middleware вокруг HttpClient, см. Middleware
+	Middlewares []Middleware
 }
 
 // опции для метода Schedules
@@ -53,29 +62,70 @@ type ScheduleId int
 type LoggingTimeId int
 type PeriodId int
 
+// Методы с суффиксом Context принимают context.Context, который позволяет
+// отменить запрос или ограничить его по времени. Методы без суффикса
+// используют context.Background().
 type API interface {
 	Schedules(options *OptionsS) ([]*Schedule, error)
+	SchedulesContext(ctx context.Context, options *OptionsS) ([]*Schedule, error)
 	AddSchedule(periodId PeriodId) (*Schedule, error)
+	AddScheduleContext(ctx context.Context, periodId PeriodId) (*Schedule, error)
 	DetailSchedule(scheduleId ScheduleId) (*Schedule, error)
+	DetailScheduleContext(ctx context.Context, scheduleId ScheduleId) (*Schedule, error)
 	LoggingTimeList(scheduleId ScheduleId, options *OptionsLT) ([]*LoggingTime, error)
+	LoggingTimeListContext(ctx context.Context, scheduleId ScheduleId, options *OptionsLT) ([]*LoggingTime, error)
 	AddLoggingTime(scheduleId ScheduleId, loggingTime *AddLoggingTime) (*LoggingTime, error)
+	AddLoggingTimeContext(ctx context.Context, scheduleId ScheduleId, loggingTime *AddLoggingTime) (*LoggingTime, error)
 	DetailLoggingTime(scheduleId ScheduleId, loggingTimeId LoggingTimeId) (*LoggingTime, error)
+	DetailLoggingTimeContext(ctx context.Context, scheduleId ScheduleId, loggingTimeId LoggingTimeId) (*LoggingTime, error)
+	UpdateLoggingTime(scheduleId ScheduleId, loggingTimeId LoggingTimeId, loggingTime *EditLoggingTime) (*LoggingTime, error)
+	UpdateLoggingTimeContext(ctx context.Context, scheduleId ScheduleId, loggingTimeId LoggingTimeId, loggingTime *EditLoggingTime) (*LoggingTime, error)
 	DeleteLoggingTime(scheduleId ScheduleId, loggingTimeId LoggingTimeId) error
+	DeleteLoggingTimeContext(ctx context.Context, scheduleId ScheduleId, loggingTimeId LoggingTimeId) error
 	SubmitForApproveSchedule(scheduleId ScheduleId) (*Schedule, error)
+	SubmitForApproveScheduleContext(ctx context.Context, scheduleId ScheduleId) (*Schedule, error)
 	ApproveLoggingTime(scheduleId ScheduleId, loggingTimeId LoggingTimeId, comment string) (*LoggingTime, error)
+	ApproveLoggingTimeContext(ctx context.Context, scheduleId ScheduleId, loggingTimeId LoggingTimeId, comment string) (*LoggingTime, error)
 	DeclineLoggingTime(scheduleId ScheduleId, loggingTimeId LoggingTimeId, comment string) (*LoggingTime, error)
+	DeclineLoggingTimeContext(ctx context.Context, scheduleId ScheduleId, loggingTimeId LoggingTimeId, comment string) (*LoggingTime, error)
+	Projects(options *OptionsP) ([]*Project, error)
+	ProjectsContext(ctx context.Context, options *OptionsP) ([]*Project, error)
+	WorkKinds(options *OptionsWK) ([]*WorkKind, error)
+	WorkKindsContext(ctx context.Context, options *OptionsWK) ([]*WorkKind, error)
+	Periods(options *OptionsPD) ([]*Period, error)
+	PeriodsContext(ctx context.Context, options *OptionsPD) ([]*Period, error)
 }
 
+// Клиент можно использовать из нескольких горутин одновременно.
+// Токены обновляются клиентом при ответе 401, поэтому после начала работы
+// читайте их методом Tokens, а не через поля AccessToken и RefreshToken.
 type Client struct {
 	BaseURL      string
 	AccessToken  string
 	RefreshToken string
 	HttpClient   HttpClient
+	// политика повторов, nil - запросы не повторяются
+	RetryPolicy *RetryPolicy
+	// журнал, nil - записи не пишутся
+	Logger logging.Logger
+	// цепочка middleware вокруг HttpClient, первая получает запрос первой
+	Middlewares []Middleware
+
+	tokenMu    sync.Mutex
+	refreshing *refreshCall
 }
 
 func NewClient(email string, password string, options *OptionsNC) (API, error) {
+	return NewClientContext(context.Background(), email, password, options)
+}
+
+func NewClientContext(ctx context.Context, email string, password string, options *OptionsNC) (API, error) {
 	baseURL := BaseURL
 	httpTimeout := 2 * time.Second
+	var httpClient HttpClient
+	var retryPolicy *RetryPolicy
+	var logger logging.Logger
+	var middlewares []Middleware
 	if options != nil {
 		if options.SuftAPIURL != "" {
 			baseURL = options.SuftAPIURL
@@ -83,31 +133,52 @@ func NewClient(email string, password string, options *OptionsNC) (API, error) {
 		if options.HttpTimeout != 0 {
 			httpTimeout = options.HttpTimeout
 		}
+		httpClient = options.HttpClient
+		retryPolicy = options.RetryPolicy
+		logger = options.Logger
+		middlewares = options.Middlewares
+	}
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{
+			Timeout: httpTimeout,
+		}
 	}
 
 	authOptions := auth.Options{
-		SuftAPIURL:  baseURL,
-		HttpTimeout: httpTimeout,
+		SuftAPIURL: baseURL,
+		HttpClient: Chain(httpClient, middlewares...),
+		Logger:     logger,
 	}
 
-	token, err := auth.Authenticate(email, password, &authOptions)
+	token, err := auth.AuthenticateContext(ctx, email, password, &authOptions)
+	authErr := &auth.AuthError{}
+	if errors.As(err, &authErr) {
+		return nil, newAPIError(http.MethodPost, auth.AuthURL, authErr.StatusCode, authErr.Body)
+	}
 	if err != nil {
 		return nil, err
 	}
-	httpClient = &http.Client{
-		Timeout: httpTimeout,
-	}
 	return &Client{
 		BaseURL:      baseURL,
 		AccessToken:  token.AccessToken,
 		RefreshToken: token.RefreshToken,
 		HttpClient:   httpClient,
+		RetryPolicy:  retryPolicy,
+		Logger:       logger,
+		Middlewares:  middlewares,
 	}, nil
 }
 
 func (c *Client) Schedules(options *OptionsS) ([]*Schedule, error) {
+	return c.SchedulesContext(context.Background(), options)
+}
+
+func (c *Client) SchedulesContext(ctx context.Context, options *OptionsS) ([]*Schedule, error) {
 	page := 0
-	size := 5
+	size := defaultPageSize
 	creatorApprover := Creator
 	if options != nil {
 		page = options.Page
@@ -119,24 +190,23 @@ func (c *Client) Schedules(options *OptionsS) ([]*Schedule, error) {
 		}
 	}
 	URN := fmt.Sprint(SchedulesURN, "?page=", page, "&size=", size, "&creatorApprover=", creatorApprover)
-	resp, err := c.doHTTP(http.MethodGet, URN, nil)
+	resp, err := c.doHTTP(ctx, http.MethodGet, URN, nil)
 	if err != nil {
-		log.Println(err)
 		return nil, err
 	}
 	defer resp.Body.Close()
 	respB, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Println("Schedules: unable to read response body:", err)
+		c.logError(http.MethodGet, URN, "Schedules: unable to read response body", err)
 		return nil, err
 	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, errors.New(string(respB))
+		return nil, newAPIError(http.MethodGet, URN, resp.StatusCode, respB)
 	}
 	schedulesResp := make([]*Schedule, 1)
 	err = json.Unmarshal(respB, &schedulesResp)
 	if err != nil {
-		log.Println("Schedules: unable to unmarshal response body:", err)
+		c.logError(http.MethodGet, URN, "Schedules: unable to unmarshal response body", err)
 		return nil, err
 	}
 
@@ -147,6 +217,10 @@ func (c *Client) Schedules(options *OptionsS) ([]*Schedule, error) {
 }
 
 func (c *Client) AddSchedule(periodId PeriodId) (*Schedule, error) {
+	return c.AddScheduleContext(context.Background(), periodId)
+}
+
+func (c *Client) AddScheduleContext(ctx context.Context, periodId PeriodId) (*Schedule, error) {
 	peiodIdStruct := struct {
 		PeriodId PeriodId `json:"periodId"`
 	}{PeriodId: periodId}
@@ -154,7 +228,7 @@ func (c *Client) AddSchedule(periodId PeriodId) (*Schedule, error) {
 	if err != nil {
 		return nil, err
 	}
-	resp, err := c.doHTTP(http.MethodPost, SchedulesURN, reqB)
+	resp, err := c.doHTTP(ctx, http.MethodPost, SchedulesURN, reqB)
 	if err != nil {
 		return nil, err
 	}
@@ -162,17 +236,17 @@ func (c *Client) AddSchedule(periodId PeriodId) (*Schedule, error) {
 
 	respB, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Println("AddSchedule: unable to read response body:", err)
+		c.logError(http.MethodPost, SchedulesURN, "AddSchedule: unable to read response body", err)
 		return nil, err
 	}
 	if resp.StatusCode != http.StatusCreated {
-		return nil, errors.New(string(respB))
+		return nil, newAPIError(http.MethodPost, SchedulesURN, resp.StatusCode, respB)
 	}
 
 	schedule := Schedule{}
 	err = json.Unmarshal(respB, &schedule)
 	if err != nil {
-		log.Println("AddSchedule: unable to unmarshal response body:", err)
+		c.logError(http.MethodPost, SchedulesURN, "AddSchedule: unable to unmarshal response body", err)
 		return nil, err
 	}
 	schedule.client = c
@@ -180,26 +254,29 @@ func (c *Client) AddSchedule(periodId PeriodId) (*Schedule, error) {
 }
 
 func (c *Client) DetailSchedule(scheduleId ScheduleId) (*Schedule, error) {
+	return c.DetailScheduleContext(context.Background(), scheduleId)
+}
+
+func (c *Client) DetailScheduleContext(ctx context.Context, scheduleId ScheduleId) (*Schedule, error) {
 
 	URN := fmt.Sprintf("%s/%d", SchedulesURN, scheduleId)
-	resp, err := c.doHTTP(http.MethodGet, URN, nil)
+	resp, err := c.doHTTP(ctx, http.MethodGet, URN, nil)
 	if err != nil {
-		log.Println(err)
 		return nil, err
 	}
 	defer resp.Body.Close()
 	respB, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Println("DetailSchedule: unable to read response body:", err)
+		c.logError(http.MethodGet, URN, "DetailSchedule: unable to read response body", err)
 		return nil, err
 	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, errors.New(string(respB))
+		return nil, newAPIError(http.MethodGet, URN, resp.StatusCode, respB)
 	}
 	schedule := Schedule{}
 	err = json.Unmarshal(respB, &schedule)
 	if err != nil {
-		log.Println("DetailSchedule: unable to unmarshal response body:", err)
+		c.logError(http.MethodGet, URN, "DetailSchedule: unable to unmarshal response body", err)
 		return nil, err
 	}
 	schedule.client = c
@@ -208,8 +285,12 @@ func (c *Client) DetailSchedule(scheduleId ScheduleId) (*Schedule, error) {
 }
 
 func (c *Client) LoggingTimeList(scheduleId ScheduleId, options *OptionsLT) ([]*LoggingTime, error) {
+	return c.LoggingTimeListContext(context.Background(), scheduleId, options)
+}
+
+func (c *Client) LoggingTimeListContext(ctx context.Context, scheduleId ScheduleId, options *OptionsLT) ([]*LoggingTime, error) {
 	page := 0
-	size := 5
+	size := defaultPageSize
 	if options != nil {
 		page = options.Page
 		if options.Size != 0 {
@@ -218,24 +299,23 @@ func (c *Client) LoggingTimeList(scheduleId ScheduleId, options *OptionsLT) ([]*
 	}
 	URN := fmt.Sprintf("%s/%d/%s?page=%d&size=%d", SchedulesURN, scheduleId, LoggingTimeURN, page, size)
 
-	resp, err := c.doHTTP(http.MethodGet, URN, nil)
+	resp, err := c.doHTTP(ctx, http.MethodGet, URN, nil)
 	if err != nil {
-		log.Println(err)
 		return nil, err
 	}
 	defer resp.Body.Close()
 	respB, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Println("LoggingTimeList: unable to read response body:", err)
+		c.logError(http.MethodGet, URN, "LoggingTimeList: unable to read response body", err)
 		return nil, err
 	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, errors.New(string(respB))
+		return nil, newAPIError(http.MethodGet, URN, resp.StatusCode, respB)
 	}
 	loggingTimes := make([]*LoggingTime, 1)
 	err = json.Unmarshal(respB, &loggingTimes)
 	if err != nil {
-		log.Println("LoggingTimeList: unable to unmarshal response body:", err)
+		c.logError(http.MethodGet, URN, "LoggingTimeList: unable to unmarshal response body", err)
 		return nil, err
 	}
 	for _, loggingTime := range loggingTimes {
@@ -248,6 +328,10 @@ func (c *Client) LoggingTimeList(scheduleId ScheduleId, options *OptionsLT) ([]*
 }
 
 func (c *Client) AddLoggingTime(scheduleId ScheduleId, loggingTime *AddLoggingTime) (*LoggingTime, error) {
+	return c.AddLoggingTimeContext(context.Background(), scheduleId, loggingTime)
+}
+
+func (c *Client) AddLoggingTimeContext(ctx context.Context, scheduleId ScheduleId, loggingTime *AddLoggingTime) (*LoggingTime, error) {
 
 	reqB, err := json.Marshal(loggingTime)
 	if err != nil {
@@ -255,7 +339,16 @@ func (c *Client) AddLoggingTime(scheduleId ScheduleId, loggingTime *AddLoggingTi
 	}
 
 	URN := fmt.Sprintf("%s/%d/%s", SchedulesURN, scheduleId, LoggingTimeURN)
-	resp, err := c.doHTTP(http.MethodPost, URN, reqB)
+	var resp *http.Response
+	if c.RetryPolicy.retriesAddLoggingTime() {
+		var created *LoggingTime
+		resp, created, err = c.addLoggingTimeWithRetry(ctx, scheduleId, URN, loggingTime, reqB)
+		if created != nil {
+			return created, nil
+		}
+	} else {
+		resp, err = c.doHTTP(ctx, http.MethodPost, URN, reqB)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -263,17 +356,17 @@ func (c *Client) AddLoggingTime(scheduleId ScheduleId, loggingTime *AddLoggingTi
 
 	respB, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Println("AddLoggingTime: unable to read response body:", err)
+		c.logError(http.MethodPost, URN, "AddLoggingTime: unable to read response body", err)
 		return nil, err
 	}
 	if resp.StatusCode != http.StatusCreated {
-		return nil, errors.New(string(respB))
+		return nil, newAPIError(http.MethodPost, URN, resp.StatusCode, respB)
 	}
 
 	loggingTimeResp := LoggingTime{}
 	err = json.Unmarshal(respB, &loggingTimeResp)
 	if err != nil {
-		log.Println("AddLoggingTime: unable to unmarshal response body:", err)
+		c.logError(http.MethodPost, URN, "AddLoggingTime: unable to unmarshal response body", err)
 		return nil, err
 	}
 	loggingTimeResp.client = c
@@ -283,8 +376,12 @@ func (c *Client) AddLoggingTime(scheduleId ScheduleId, loggingTime *AddLoggingTi
 }
 
 func (c *Client) DetailLoggingTime(scheduleId ScheduleId, loggingTimeId LoggingTimeId) (*LoggingTime, error) {
+	return c.DetailLoggingTimeContext(context.Background(), scheduleId, loggingTimeId)
+}
+
+func (c *Client) DetailLoggingTimeContext(ctx context.Context, scheduleId ScheduleId, loggingTimeId LoggingTimeId) (*LoggingTime, error) {
 	URN := fmt.Sprintf("%s/%d/%s/%d", SchedulesURN, scheduleId, LoggingTimeURN, loggingTimeId)
-	resp, err := c.doHTTP(http.MethodGet, URN, nil)
+	resp, err := c.doHTTP(ctx, http.MethodGet, URN, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -292,16 +389,16 @@ func (c *Client) DetailLoggingTime(scheduleId ScheduleId, loggingTimeId LoggingT
 
 	respB, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Println("DetailLoggingTime: unable to read response body:", err)
+		c.logError(http.MethodGet, URN, "DetailLoggingTime: unable to read response body", err)
 		return nil, err
 	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, errors.New(string(respB))
+		return nil, newAPIError(http.MethodGet, URN, resp.StatusCode, respB)
 	}
 	loggingTime := LoggingTime{}
 	err = json.Unmarshal(respB, &loggingTime)
 	if err != nil {
-		log.Println("DetailLoggingTime: unable to unmarshal response body:", err)
+		c.logError(http.MethodGet, URN, "DetailLoggingTime: unable to unmarshal response body", err)
 		return nil, err
 	}
 	loggingTime.client = c
@@ -309,26 +406,75 @@ func (c *Client) DetailLoggingTime(scheduleId ScheduleId, loggingTimeId LoggingT
 	return &loggingTime, nil
 }
 
+func (c *Client) UpdateLoggingTime(scheduleId ScheduleId, loggingTimeId LoggingTimeId, loggingTime *EditLoggingTime) (*LoggingTime, error) {
+	return c.UpdateLoggingTimeContext(context.Background(), scheduleId, loggingTimeId, loggingTime)
+}
+
+func (c *Client) UpdateLoggingTimeContext(ctx context.Context, scheduleId ScheduleId, loggingTimeId LoggingTimeId, loggingTime *EditLoggingTime) (*LoggingTime, error) {
+	reqB, err := json.Marshal(loggingTime)
+	if err != nil {
+		c.logError(http.MethodPatch, "", "UpdateLoggingTime: unable to marshal body", err)
+		return nil, err
+	}
+
+	URN := fmt.Sprintf("%s/%d/%s/%d", SchedulesURN, scheduleId, LoggingTimeURN, loggingTimeId)
+
+	resp, err := c.doHTTP(ctx, http.MethodPatch, URN, reqB)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	respB, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.logError(http.MethodPatch, URN, "UpdateLoggingTime: unable to read response body", err)
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(http.MethodPatch, URN, resp.StatusCode, respB)
+	}
+
+	loggingTimeResp := LoggingTime{}
+
+	err = json.Unmarshal(respB, &loggingTimeResp)
+	if err != nil {
+		c.logError(http.MethodPatch, URN, "UpdateLoggingTime: unable to unmarshal response body", err)
+		return nil, err
+	}
+	loggingTimeResp.client = c
+	loggingTimeResp.scheduleId = scheduleId
+	return &loggingTimeResp, nil
+}
 
 func (c *Client) DeleteLoggingTime(scheduleId ScheduleId, loggingTimeId LoggingTimeId) error {
+	return c.DeleteLoggingTimeContext(context.Background(), scheduleId, loggingTimeId)
+}
+
+func (c *Client) DeleteLoggingTimeContext(ctx context.Context, scheduleId ScheduleId, loggingTimeId LoggingTimeId) error {
 	URN := fmt.Sprintf("%s/%d/%s/%d", SchedulesURN, scheduleId, LoggingTimeURN, loggingTimeId)
-	resp, err := c.doHTTP(http.MethodDelete, URN, nil)
+	resp, err := c.doHTTP(ctx, http.MethodDelete, URN, nil)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 	respB, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Println("DeleteLoggingTime: unable to read response body:", err)
+		c.logError(http.MethodDelete, URN, "DeleteLoggingTime: unable to read response body", err)
 		return err
 	}
 	if resp.StatusCode != http.StatusOK {
-		return errors.New(string(respB))
+		return newAPIError(http.MethodDelete, URN, resp.StatusCode, respB)
 	}
 	return nil
 }
 
 func (c *Client) SubmitForApproveSchedule(scheduleId ScheduleId) (*Schedule, error) {
+	return c.SubmitForApproveScheduleContext(context.Background(), scheduleId)
+}
+
+func (c *Client) SubmitForApproveScheduleContext(ctx context.Context, scheduleId ScheduleId) (*Schedule, error) {
 	URN := fmt.Sprintf("%s/%d", SchedulesURN, scheduleId)
 
 	statusCodeStruct := struct {
@@ -338,32 +484,31 @@ func (c *Client) SubmitForApproveSchedule(scheduleId ScheduleId) (*Schedule, err
 	}
 	reqB, err := json.Marshal(statusCodeStruct)
 	if err != nil {
-		log.Println("SubmitForApproveSchedule: unable to marshal body:", err)
+		c.logError(http.MethodPatch, "", "SubmitForApproveSchedule: unable to marshal body", err)
 		return nil, err
 	}
 
-	resp, err := c.doHTTP(http.MethodPatch, URN, reqB)
+	resp, err := c.doHTTP(ctx, http.MethodPatch, URN, reqB)
 	if err != nil {
-		log.Println("SubmitForApproveSchedule: doHTTP:", err)
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	respB, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Println("SubmitForApproveSchedule: unable to read response body:", err)
+		c.logError(http.MethodPatch, URN, "SubmitForApproveSchedule: unable to read response body", err)
 		return nil, err
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, errors.New(string(respB))
+		return nil, newAPIError(http.MethodPatch, URN, resp.StatusCode, respB)
 	}
 
 	schedule := Schedule{}
 
 	err = json.Unmarshal(respB, &schedule)
 	if err != nil {
-		log.Println("SubmitForApproveSchedule: unable to unmarshal response body:", err)
+		c.logError(http.MethodPatch, URN, "SubmitForApproveSchedule: unable to unmarshal response body", err)
 		return nil, err
 	}
 	schedule.client = c
@@ -372,6 +517,10 @@ func (c *Client) SubmitForApproveSchedule(scheduleId ScheduleId) (*Schedule, err
 }
 
 func (c *Client) ApproveLoggingTime(scheduleId ScheduleId, loggingTimeId LoggingTimeId, comment string) (*LoggingTime, error) {
+	return c.ApproveLoggingTimeContext(context.Background(), scheduleId, loggingTimeId, comment)
+}
+
+func (c *Client) ApproveLoggingTimeContext(ctx context.Context, scheduleId ScheduleId, loggingTimeId LoggingTimeId, comment string) (*LoggingTime, error) {
 	editLoggingTime := EditLoggingTime{
 		CommentAdminEmployee: comment,
 		StatusCode:           Approved,
@@ -379,15 +528,14 @@ func (c *Client) ApproveLoggingTime(scheduleId ScheduleId, loggingTimeId Logging
 
 	reqB, err := json.Marshal(&editLoggingTime)
 	if err != nil {
-		log.Println("ApproveLoggingTime: unable to marshal body:", err)
+		c.logError(http.MethodPatch, "", "ApproveLoggingTime: unable to marshal body", err)
 		return nil, err
 	}
 
 	URN := fmt.Sprintf("%s/%d/%s/%d", SchedulesURN, scheduleId, LoggingTimeURN, loggingTimeId)
 
-	resp, err := c.doHTTP(http.MethodPatch, URN, reqB)
+	resp, err := c.doHTTP(ctx, http.MethodPatch, URN, reqB)
 	if err != nil {
-		log.Println("ApproveLoggingTime: doHTTP:", err)
 		return nil, err
 	}
 
@@ -395,19 +543,19 @@ func (c *Client) ApproveLoggingTime(scheduleId ScheduleId, loggingTimeId Logging
 
 	respB, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Println("ApproveLoggingTime: unable to read response body:", err)
+		c.logError(http.MethodPatch, URN, "ApproveLoggingTime: unable to read response body", err)
 		return nil, err
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, errors.New(string(respB))
+		return nil, newAPIError(http.MethodPatch, URN, resp.StatusCode, respB)
 	}
 
 	loggingTimeResp := LoggingTime{}
 
 	err = json.Unmarshal(respB, &loggingTimeResp)
 	if err != nil {
-		log.Println("ApproveLoggingTime: unable to unmarshal response body:", err)
+		c.logError(http.MethodPatch, URN, "ApproveLoggingTime: unable to unmarshal response body", err)
 		return nil, err
 	}
 	loggingTimeResp.client = c
@@ -416,6 +564,10 @@ func (c *Client) ApproveLoggingTime(scheduleId ScheduleId, loggingTimeId Logging
 }
 
 func (c *Client) DeclineLoggingTime(scheduleId ScheduleId, loggingTimeId LoggingTimeId, comment string) (*LoggingTime, error) {
+	return c.DeclineLoggingTimeContext(context.Background(), scheduleId, loggingTimeId, comment)
+}
+
+func (c *Client) DeclineLoggingTimeContext(ctx context.Context, scheduleId ScheduleId, loggingTimeId LoggingTimeId, comment string) (*LoggingTime, error) {
 	editLoggingTime := EditLoggingTime{
 		CommentAdminEmployee: comment,
 		StatusCode:           Declined,
@@ -423,15 +575,14 @@ func (c *Client) DeclineLoggingTime(scheduleId ScheduleId, loggingTimeId Logging
 
 	reqB, err := json.Marshal(&editLoggingTime)
 	if err != nil {
-		log.Println("DeclineLoggingTime: unable to marshal body:", err)
+		c.logError(http.MethodPatch, "", "DeclineLoggingTime: unable to marshal body", err)
 		return nil, err
 	}
 
 	URN := fmt.Sprintf("%s/%d/%s/%d", SchedulesURN, scheduleId, LoggingTimeURN, loggingTimeId)
 
-	resp, err := c.doHTTP(http.MethodPatch, URN, reqB)
+	resp, err := c.doHTTP(ctx, http.MethodPatch, URN, reqB)
 	if err != nil {
-		log.Println("DeclineLoggingTime: doHTTP:", err)
 		return nil, err
 	}
 
@@ -439,19 +590,19 @@ func (c *Client) DeclineLoggingTime(scheduleId ScheduleId, loggingTimeId Logging
 
 	respB, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Println("DeclineLoggingTime: unable to read response body:", err)
+		c.logError(http.MethodPatch, URN, "DeclineLoggingTime: unable to read response body", err)
 		return nil, err
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, errors.New(string(respB))
+		return nil, newAPIError(http.MethodPatch, URN, resp.StatusCode, respB)
 	}
 
 	loggingTimeResp := LoggingTime{}
 
 	err = json.Unmarshal(respB, &loggingTimeResp)
 	if err != nil {
-		log.Println("DeclineLoggingTime: unable to unmarshal response body:", err)
+		c.logError(http.MethodPatch, URN, "DeclineLoggingTime: unable to unmarshal response body", err)
 		return nil, err
 	}
 	loggingTimeResp.client = c
@@ -459,62 +610,80 @@ func (c *Client) DeclineLoggingTime(scheduleId ScheduleId, loggingTimeId Logging
 	return &loggingTimeResp, nil
 }
 
-func (c *Client) doHTTP(httpMethod string, URN string, body []byte) (*http.Response, error) {
-	reqBody := bytes.NewBuffer(body)
-	req1, err := http.NewRequest(
-		httpMethod,
-		fmt.Sprint(BaseURL, URN),
-		reqBody,
-	)
-	if err != nil {
-		log.Println(err)
-		return nil, err
+// адрес API клиента, по умолчанию BaseURL
+func (c *Client) baseURL() string {
+	if c.BaseURL == "" {
+		return BaseURL
 	}
-	req1.Header.Add("Auth-method", "Password")
-	req1.Header.Add("Content-Type", "application/json")
-	req1.Header.Add("Accept-Charset", "UTF-8")
+	if !strings.HasSuffix(c.BaseURL, "/") {
+		return c.BaseURL + "/"
+	}
+	return c.BaseURL
+}
 
-	cookieAccessToken := &http.Cookie{
-		Name:  "Access-token",
-		Value: c.AccessToken,
+// HttpClient клиента вместе с цепочкой Middlewares
+func (c *Client) httpClient() HttpClient {
+	return Chain(c.HttpClient, c.Middlewares...)
+}
+
+func (c *Client) doHTTP(ctx context.Context, httpMethod string, URN string, body []byte) (*http.Response, error) {
+	if c.RetryPolicy == nil || !c.RetryPolicy.withDefaults().allowsMethod(httpMethod) {
+		return c.doRequest(ctx, httpMethod, URN, body)
 	}
-	req1.AddCookie(cookieAccessToken)
+	return c.withRetry(ctx, httpMethod, URN, func(attempt int) (*http.Response, error) {
+		return c.doRequest(ctx, httpMethod, URN, body)
+	})
+}
 
-	resp, err := c.HttpClient.Do(req1)
+func (c *Client) doRequest(ctx context.Context, httpMethod string, URN string, body []byte) (*http.Response, error) {
+	accessToken := c.accessToken()
+	resp, err := c.send(ctx, httpMethod, URN, body, accessToken)
 	if err != nil {
-		log.Println(err)
 		return nil, err
 	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		return resp, nil
+	}
 
-	if resp.StatusCode == http.StatusUnauthorized {
-		tokens, err := auth.Refresh(c.RefreshToken, nil)
-		if err != nil {
-			log.Println(err)
-			return nil, err
-		}
-		c.AccessToken = tokens.AccessToken
-		c.RefreshToken = tokens.RefreshToken
-
-		cookieAccessToken.Value = tokens.AccessToken
-		req2, err := http.NewRequest(
-			httpMethod,
-			fmt.Sprint(BaseURL, URN),
-			reqBody,
-		)
-		if err != nil {
-			log.Println(err)
-			return nil, err
-		}
-		req2.Header.Add("Auth-method", "Password")
-		req2.Header.Add("Content-Type", "application/json")
-		req2.Header.Add("Accept-Charset", "UTF-8")
-		req2.AddCookie(cookieAccessToken)
-		resp, err = c.HttpClient.Do(req2)
-		if err != nil {
-			log.Println(err)
-			return nil, err
-		}
+	accessToken, err = c.refreshTokens(ctx, accessToken)
+	if errors.Is(err, auth.ErrRefresh) {
+		// обновить токены не удалось, вызывающий метод вернёт ошибку 401
+		return resp, nil
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
 	}
 
+	resp, err = c.send(ctx, httpMethod, URN, body, accessToken)
+	if err != nil {
+		return nil, err
+	}
 	return resp, nil
 }
+
+// отправляет один запрос. Тело создаётся заново при каждом вызове,
+// поэтому запрос можно повторить с тем же body
+func (c *Client) send(ctx context.Context, httpMethod string, URN string, body []byte, accessToken string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(
+		ctx,
+		httpMethod,
+		fmt.Sprint(c.baseURL(), URN),
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Auth-method", "Password")
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Accept-Charset", "UTF-8")
+	req.AddCookie(&http.Cookie{
+		Name:  "Access-token",
+		Value: accessToken,
+	})
+	start := time.Now()
+	resp, err := c.httpClient().Do(req)
+	c.logRequest(httpMethod, URN, resp, err, time.Since(start))
+	return resp, err
+}
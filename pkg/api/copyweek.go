@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"strings"
+)
+
+// опции для CopyWeek
+type OptionsCW struct {
+	// не переносить часы, создать строки с нулевыми часами
+	ZeroHours bool
+}
+
+// результат копирования одной временной затраты
+type CopyLine struct {
+	Source *LoggingTime
+	// созданная временная затрата, nil если строка пропущена или не создана
+	Created *LoggingTime
+	// строка с теми же проектом, видом работ и задачей уже есть в расписании
+	Skipped bool
+	Err     error
+}
+
+// результат копирования недели
+type CopyWeekResult struct {
+	// расписание, в которое копировались временные затраты
+	Schedule *Schedule
+	Lines    []CopyLine
+}
+
+// Failed возвращает количество строк, которые не удалось создать
+func (r *CopyWeekResult) Failed() int {
+	failed := 0
+	for _, line := range r.Lines {
+		if line.Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// CopyWeek копирует временные затраты расписания в расписание на период toPeriodId,
+// создавая его при необходимости. Строки, которые уже есть в расписании, пропускаются.
+// Ошибка создания отдельной строки записывается в результат и не прерывает копирование
+func CopyWeek(client API, fromScheduleId ScheduleId, toPeriodId PeriodId, options *OptionsCW) (*CopyWeekResult, error) {
+	return CopyWeekContext(context.Background(), client, fromScheduleId, toPeriodId, options)
+}
+
+func CopyWeekContext(ctx context.Context, client API, fromScheduleId ScheduleId, toPeriodId PeriodId, options *OptionsCW) (*CopyWeekResult, error) {
+	if options == nil {
+		options = &OptionsCW{}
+	}
+	source, err := AllLoggingTimesContext(ctx, client, fromScheduleId, nil)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := EnsureScheduleForPeriodContext(ctx, client, toPeriodId)
+	if err != nil {
+		return nil, err
+	}
+	if err := schedule.StatusCode.Check(ActionEdit); err != nil {
+		return nil, err
+	}
+	scheduleId := ScheduleId(schedule.Id)
+	existing, err := AllLoggingTimesContext(ctx, client, scheduleId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CopyWeekResult{Schedule: schedule}
+	for _, loggingTime := range source {
+		line := CopyLine{Source: loggingTime}
+		if containsLine(existing, loggingTime) {
+			line.Skipped = true
+			result.Lines = append(result.Lines, line)
+			continue
+		}
+		addLoggingTime := NewAddLoggingTime(loggingTime)
+		if options.ZeroHours {
+			for _, hours := range addLoggingTime.days() {
+				*hours = 0
+			}
+		}
+		line.Created, line.Err = client.AddLoggingTimeContext(ctx, scheduleId, addLoggingTime)
+		if line.Err == nil {
+			existing = append(existing, line.Created)
+		} else if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		result.Lines = append(result.Lines, line)
+	}
+	return result, nil
+}
+
+// проверяет, есть ли среди временных затрат строка с теми же проектом, видом работ и задачей
+func containsLine(loggingTimes []*LoggingTime, line *LoggingTime) bool {
+	for _, loggingTime := range loggingTimes {
+		if loggingTime.ProjectId == line.ProjectId &&
+			loggingTime.WorkKindId == line.WorkKindId &&
+			strings.EqualFold(strings.TrimSpace(loggingTime.Task), strings.TrimSpace(line.Task)) {
+			return true
+		}
+	}
+	return false
+}
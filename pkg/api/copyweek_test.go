@@ -0,0 +1,77 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var copySource = []LoggingTime{
+	{Id: 1, Day1Time: 8, ProjectId: 1, WorkKindId: 21, Task: "task 1"},
+	{Id: 2, Day2Time: 4, ProjectId: 2, WorkKindId: 21, Task: "task 2"},
+}
+
+func TestCopyWeek(t *testing.T) {
+	target := Schedule{Id: 40000, Period: Period{Id: 355}, StatusCode: Created}
+	client, httpClient := newRetryClient(
+		jsonResp(http.StatusOK, copySource),
+		jsonResp(http.StatusOK, []Schedule{target}),
+		jsonResp(http.StatusOK, []LoggingTime{{Id: 10, ProjectId: 2, WorkKindId: 21, Task: "Task 2 "}}),
+		jsonResp(http.StatusCreated, LoggingTime{Id: 11, Day1Time: 8, ProjectId: 1, WorkKindId: 21, Task: "task 1"}),
+	)
+	result, err := CopyWeek(client, 777, 355, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 40000, result.Schedule.Id)
+	require.Len(t, result.Lines, 2)
+	assert.Equal(t, 11, result.Lines[0].Created.Id)
+	assert.True(t, result.Lines[1].Skipped)
+	assert.Equal(t, 0, result.Failed())
+
+	require.Len(t, httpClient.requests, 4)
+	assert.Equal(t, "/tools/suft/api/v1/api/v1/schedules/40000/logging-times", httpClient.requests[3].URL.Path)
+	body, _ := ioutil.ReadAll(httpClient.requests[3].Body)
+	added := AddLoggingTime{}
+	require.NoError(t, json.Unmarshal(body, &added))
+	assert.Equal(t, 8.0, added.Day1Time)
+}
+
+func TestCopyWeekCreatesScheduleWithZeroHours(t *testing.T) {
+	client, httpClient := newRetryClient(
+		jsonResp(http.StatusOK, copySource),
+		jsonResp(http.StatusOK, []Schedule{}),
+		jsonResp(http.StatusCreated, Schedule{Id: 40000, Period: Period{Id: 355}, StatusCode: Created}),
+		jsonResp(http.StatusOK, []LoggingTime{}),
+		jsonResp(http.StatusCreated, LoggingTime{Id: 11}),
+		errorResp,
+	)
+	result, err := CopyWeek(client, 777, 355, &OptionsCW{ZeroHours: true})
+	require.NoError(t, err)
+	require.Len(t, result.Lines, 2)
+	assert.NoError(t, result.Lines[0].Err)
+	assert.Error(t, result.Lines[1].Err)
+	assert.Equal(t, 1, result.Failed())
+
+	require.Len(t, httpClient.requests, 6)
+	assert.Equal(t, http.MethodPost, httpClient.requests[2].Method)
+	body, _ := ioutil.ReadAll(httpClient.requests[4].Body)
+	added := AddLoggingTime{}
+	require.NoError(t, json.Unmarshal(body, &added))
+	assert.Equal(t, 0.0, added.TotalHours())
+	assert.Equal(t, "task 1", added.Task)
+}
+
+func TestCopyWeekTargetNotEditable(t *testing.T) {
+	client, httpClient := newRetryClient(
+		jsonResp(http.StatusOK, copySource),
+		jsonResp(http.StatusOK, []Schedule{{Id: 40000, Period: Period{Id: 355}, StatusCode: Approved}}),
+	)
+	result, err := CopyWeek(client, 777, 355, nil)
+	require.True(t, errors.Is(err, ErrInvalidStatus))
+	assert.Nil(t, result)
+	assert.Len(t, httpClient.requests, 2)
+}
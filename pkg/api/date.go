@@ -0,0 +1,129 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrDateOutsidePeriod возвращается, если дата не попадает в период расписания
+var ErrDateOutsidePeriod = errors.New("date is outside the period")
+
+// Date - календарная дата без времени в формате ГГГГ-ММ-ДД.
+// Нулевое значение соответствует JSON null
+type Date struct {
+	time.Time
+}
+
+// NewDate возвращает дату в UTC
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// DateOf возвращает дату, на которую приходится момент t, в часовом поясе t
+func DateOf(t time.Time) Date {
+	return NewDate(t.Year(), t.Month(), t.Day())
+}
+
+// ParseDate разбирает дату в формате ГГГГ-ММ-ДД, пустая строка даёт нулевую дату
+func ParseDate(s string) (Date, error) {
+	if s == "" {
+		return Date{}, nil
+	}
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return Date{}, err
+	}
+	return Date{t}, nil
+}
+
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(dateLayout)
+}
+
+// AddDays возвращает дату, сдвинутую на days дней
+func (d Date) AddDays(days int) Date {
+	return Date{d.AddDate(0, 0, days)}
+}
+
+// DaysUntil возвращает количество дней от d до other
+func (d Date) DaysUntil(other Date) int {
+	return int(other.Sub(d.Time).Hours() / 24)
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*d = Date{}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("date: %w", err)
+	}
+	date, err := ParseDate(s)
+	if err != nil {
+		return fmt.Errorf("date: %w", err)
+	}
+	*d = date
+	return nil
+}
+
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Date) UnmarshalText(text []byte) error {
+	date, err := ParseDate(string(text))
+	if err != nil {
+		return fmt.Errorf("date: %w", err)
+	}
+	*d = date
+	return nil
+}
+
+// Contains сообщает, попадает ли дата в период. Если у периода нет дат,
+// сравнивается номер недели
+func (p *Period) Contains(date time.Time) bool {
+	day := DateOf(date)
+	if !p.StartDate.IsZero() && !p.EndDate.IsZero() {
+		return !day.Before(p.StartDate.Time) && !day.After(p.EndDate.Time)
+	}
+	_, week := date.ISOWeek()
+	return p.WeekNumber == week
+}
+
+// Dates возвращает все даты периода по порядку
+func (p *Period) Dates() []Date {
+	if p.StartDate.IsZero() || p.EndDate.IsZero() {
+		return nil
+	}
+	dates := []Date{}
+	for day := p.StartDate; !day.After(p.EndDate.Time); day = day.AddDays(1) {
+		dates = append(dates, day)
+	}
+	return dates
+}
+
+// DayNumber возвращает номер дня периода (1 - первый день) для поля DayNTime
+func (p *Period) DayNumber(date time.Time) (int, error) {
+	if p.StartDate.IsZero() {
+		return 0, fmt.Errorf("%w: period %d has no start date", ErrDateOutsidePeriod, p.Id)
+	}
+	number := p.StartDate.DaysUntil(DateOf(date)) + 1
+	if number < 1 || number > daysInWeek || (!p.EndDate.IsZero() && DateOf(date).After(p.EndDate.Time)) {
+		return 0, fmt.Errorf("%w: %s not in period %d", ErrDateOutsidePeriod, DateOf(date), p.Id)
+	}
+	return number, nil
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeriodJSON(t *testing.T) {
+	period := Period{}
+	err := json.Unmarshal([]byte(`{"closeDate":null,"endDate":"2021-02-21","id":354,"startDate":"2021-02-15","weekNumber":7}`), &period)
+	require.NoError(t, err)
+	assert.True(t, period.CloseDate.IsZero())
+	assert.Equal(t, NewDate(2021, 2, 15), period.StartDate)
+	assert.Equal(t, NewDate(2021, 2, 21), period.EndDate)
+
+	periodB, err := json.Marshal(period)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"closeDate":null,"endDate":"2021-02-21","id":354,"startDate":"2021-02-15","weekNumber":7}`, string(periodB))
+}
+
+func TestDateUnmarshalError(t *testing.T) {
+	date := Date{}
+	require.Error(t, json.Unmarshal([]byte(`"15.02.2021"`), &date))
+	require.Error(t, json.Unmarshal([]byte(`20210215`), &date))
+	require.NoError(t, json.Unmarshal([]byte(`""`), &date))
+	assert.True(t, date.IsZero())
+}
+
+func TestPeriodDayNumber(t *testing.T) {
+	period := Period{Id: 354, StartDate: NewDate(2021, 2, 15), EndDate: NewDate(2021, 2, 21)}
+	number, err := period.DayNumber(time.Date(2021, 2, 17, 23, 59, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, 3, number)
+
+	_, err = period.DayNumber(time.Date(2021, 2, 22, 0, 0, 0, 0, time.UTC))
+	require.ErrorIs(t, err, ErrDateOutsidePeriod)
+	_, err = period.DayNumber(time.Date(2021, 2, 14, 0, 0, 0, 0, time.UTC))
+	require.ErrorIs(t, err, ErrDateOutsidePeriod)
+
+	_, err = (&Period{Id: 1}).DayNumber(time.Now())
+	require.ErrorIs(t, err, ErrDateOutsidePeriod)
+}
+
+func TestPeriodDates(t *testing.T) {
+	period := Period{StartDate: NewDate(2021, 2, 15), EndDate: NewDate(2021, 2, 21)}
+	dates := period.Dates()
+	require.Len(t, dates, 7)
+	assert.Equal(t, NewDate(2021, 2, 15), dates[0])
+	assert.Equal(t, NewDate(2021, 2, 21), dates[6])
+	assert.Nil(t, (&Period{}).Dates())
+}
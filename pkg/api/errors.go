@@ -0,0 +1,133 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"suftsdk/internal/auth"
+)
+
+// ошибки, с которыми можно сравнивать результат методов клиента через errors.Is
+var (
+	// в том числе неверный логин или пароль при аутентификации
+	ErrUnauthorized = auth.ErrUnauthorized
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	// действие недопустимо в текущем статусе, проверяется на клиенте до запроса
+	ErrInvalidStatus = errors.New("action not allowed in current status")
+)
+
+// тело ответа, которое сервер СУФТ возвращает при ошибке
+type ErrorBody struct {
+	Timestamp string `json:"timestamp"`
+	Status    int    `json:"status"`
+	Error     string `json:"error"`
+	Message   string `json:"message"`
+	Path      string `json:"path"`
+}
+
+// ошибка, возвращаемая методами клиента при ответе сервера с неуспешным статусом
+type APIError struct {
+	StatusCode int
+	Method     string
+	URN        string
+	// разобранное тело ответа, nil если сервер вернул не JSON
+	Body *ErrorBody
+	// тело ответа без изменений
+	RawBody []byte
+}
+
+func newAPIError(method string, URN string, statusCode int, respB []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Method:     method,
+		URN:        URN,
+		RawBody:    respB,
+	}
+	errorBody := ErrorBody{}
+	if json.Unmarshal(respB, &errorBody) == nil {
+		apiErr.Body = &errorBody
+	}
+	return apiErr
+}
+
+// Message возвращает текст ошибки от сервера
+func (e *APIError) Message() string {
+	if e.Body != nil {
+		if e.Body.Message != "" {
+			return e.Body.Message
+		}
+		if e.Body.Error != "" {
+			return e.Body.Error
+		}
+	}
+	return strings.TrimSpace(string(e.RawBody))
+}
+
+func (e *APIError) Error() string {
+	message := e.Message()
+	if message == "" {
+		message = http.StatusText(e.StatusCode)
+	}
+	return fmt.Sprintf("%s %s: %d %s", e.Method, e.URN, e.StatusCode, message)
+}
+
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	}
+	return false
+}
+
+// ошибка, возвращаемая до запроса к серверу, если действие недопустимо в текущем статусе.
+// Сравнивается с ErrInvalidStatus и ErrConflict
+type StatusError struct {
+	Action Action
+	Status StatusCode
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s is not allowed in status %s (%s)", e.Action, e.Status, e.Status.LabelEn())
+}
+
+func (e *StatusError) Is(target error) bool {
+	return target == ErrInvalidStatus || target == ErrConflict
+}
+
+// ошибка поиска по дате, если ни один период не содержит дату. Сравнивается с ErrNotFound
+type PeriodNotFoundError struct {
+	Date time.Time
+}
+
+func (e *PeriodNotFoundError) Error() string {
+	return fmt.Sprintf("%s: period for date %s", ErrNotFound, e.Date.Format(dateLayout))
+}
+
+func (e *PeriodNotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// ошибка поиска расписания пользователя на период, если расписания нет. Сравнивается с ErrNotFound
+type ScheduleNotFoundError struct {
+	PeriodId PeriodId
+}
+
+func (e *ScheduleNotFoundError) Error() string {
+	return fmt.Sprintf("%s: schedule for period %d", ErrNotFound, e.PeriodId)
+}
+
+func (e *ScheduleNotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
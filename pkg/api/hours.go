@@ -0,0 +1,146 @@
+package api
+
+import "time"
+
+const daysInWeek = 7
+
+// weekdayNumber возвращает номер дня недели для поля DayNTime: понедельник - 1, воскресенье - 7
+func weekdayNumber(weekday time.Weekday) int {
+	if weekday == time.Sunday {
+		return daysInWeek
+	}
+	return int(weekday)
+}
+
+func (l *LoggingTime) days() [daysInWeek]*float64 {
+	return [daysInWeek]*float64{&l.Day1Time, &l.Day2Time, &l.Day3Time, &l.Day4Time, &l.Day5Time, &l.Day6Time, &l.Day7Time}
+}
+
+func (l *AddLoggingTime) days() [daysInWeek]*float64 {
+	return [daysInWeek]*float64{&l.Day1Time, &l.Day2Time, &l.Day3Time, &l.Day4Time, &l.Day5Time, &l.Day6Time, &l.Day7Time}
+}
+
+func (l *EditLoggingTime) days() [daysInWeek]*float64 {
+	return [daysInWeek]*float64{&l.Day1Time, &l.Day2Time, &l.Day3Time, &l.Day4Time, &l.Day5Time, &l.Day6Time, &l.Day7Time}
+}
+
+func totalHours(days [daysInWeek]*float64) float64 {
+	total := 0.0
+	for _, hours := range days {
+		total += *hours
+	}
+	return total
+}
+
+func hoursByDate(days [daysInWeek]*float64, period *Period, date time.Time) (float64, error) {
+	number, err := period.DayNumber(date)
+	if err != nil {
+		return 0, err
+	}
+	return *days[number-1], nil
+}
+
+func setHoursByDate(days [daysInWeek]*float64, period *Period, date time.Time, hours float64) error {
+	number, err := period.DayNumber(date)
+	if err != nil {
+		return err
+	}
+	*days[number-1] = hours
+	return nil
+}
+
+func hoursByDay(days [daysInWeek]*float64, period *Period) map[Date]float64 {
+	result := map[Date]float64{}
+	for i, date := range period.Dates() {
+		if i >= daysInWeek {
+			break
+		}
+		result[date] = *days[i]
+	}
+	return result
+}
+
+// Hours возвращает часы за день недели
+func (l *LoggingTime) Hours(weekday time.Weekday) float64 {
+	return *l.days()[weekdayNumber(weekday)-1]
+}
+
+// SetHours задаёт часы за день недели
+func (l *LoggingTime) SetHours(weekday time.Weekday, hours float64) {
+	*l.days()[weekdayNumber(weekday)-1] = hours
+}
+
+// HoursOn возвращает часы за дату периода расписания
+func (l *LoggingTime) HoursOn(period *Period, date time.Time) (float64, error) {
+	return hoursByDate(l.days(), period, date)
+}
+
+// SetHoursOn задаёт часы за дату периода расписания
+func (l *LoggingTime) SetHoursOn(period *Period, date time.Time, hours float64) error {
+	return setHoursByDate(l.days(), period, date, hours)
+}
+
+// HoursByDate возвращает часы по датам периода расписания
+func (l *LoggingTime) HoursByDate(period *Period) map[Date]float64 {
+	return hoursByDay(l.days(), period)
+}
+
+// TotalHours возвращает сумму часов за неделю
+func (l *LoggingTime) TotalHours() float64 {
+	return totalHours(l.days())
+}
+
+// Hours возвращает часы за день недели
+func (l *AddLoggingTime) Hours(weekday time.Weekday) float64 {
+	return *l.days()[weekdayNumber(weekday)-1]
+}
+
+// SetHours задаёт часы за день недели
+func (l *AddLoggingTime) SetHours(weekday time.Weekday, hours float64) {
+	*l.days()[weekdayNumber(weekday)-1] = hours
+}
+
+// HoursOn возвращает часы за дату периода расписания
+func (l *AddLoggingTime) HoursOn(period *Period, date time.Time) (float64, error) {
+	return hoursByDate(l.days(), period, date)
+}
+
+// SetHoursOn задаёт часы за дату периода расписания
+func (l *AddLoggingTime) SetHoursOn(period *Period, date time.Time, hours float64) error {
+	return setHoursByDate(l.days(), period, date, hours)
+}
+
+// HoursByDate возвращает часы по датам периода расписания
+func (l *AddLoggingTime) HoursByDate(period *Period) map[Date]float64 {
+	return hoursByDay(l.days(), period)
+}
+
+// TotalHours возвращает сумму часов за неделю
+func (l *AddLoggingTime) TotalHours() float64 {
+	return totalHours(l.days())
+}
+
+// Hours возвращает часы за день недели
+func (l *EditLoggingTime) Hours(weekday time.Weekday) float64 {
+	return *l.days()[weekdayNumber(weekday)-1]
+}
+
+// SetHours задаёт часы за день недели
+func (l *EditLoggingTime) SetHours(weekday time.Weekday, hours float64) {
+	*l.days()[weekdayNumber(weekday)-1] = hours
+}
+
+// HoursOn возвращает часы за дату периода расписания
+func (l *EditLoggingTime) HoursOn(period *Period, date time.Time) (float64, error) {
+	return hoursByDate(l.days(), period, date)
+}
+
+// SetHoursOn задаёт часы за дату периода расписания
+func (l *EditLoggingTime) SetHoursOn(period *Period, date time.Time, hours float64) error {
+	return setHoursByDate(l.days(), period, date, hours)
+}
+
+// TotalHours возвращает сумму часов за неделю
+func (l *EditLoggingTime) TotalHours() float64 {
+	return totalHours(l.days())
+}
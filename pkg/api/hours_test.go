@@ -0,0 +1,45 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggingTimeHoursByWeekday(t *testing.T) {
+	loggingTime := LoggingTime{Day1Time: 8, Day7Time: 2}
+	assert.Equal(t, 8.0, loggingTime.Hours(time.Monday))
+	assert.Equal(t, 2.0, loggingTime.Hours(time.Sunday))
+
+	loggingTime.SetHours(time.Friday, 4)
+	assert.Equal(t, 4.0, loggingTime.Day5Time)
+	assert.Equal(t, 14.0, loggingTime.TotalHours())
+}
+
+func TestAddLoggingTimeHoursByDate(t *testing.T) {
+	period := &Period{Id: 354, StartDate: NewDate(2021, 2, 15), EndDate: NewDate(2021, 2, 21)}
+	loggingTime := AddLoggingTime{}
+	require.NoError(t, loggingTime.SetHoursOn(period, time.Date(2021, 2, 16, 10, 0, 0, 0, time.UTC), 6))
+	assert.Equal(t, 6.0, loggingTime.Day2Time)
+
+	hours, err := loggingTime.HoursOn(period, time.Date(2021, 2, 16, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, 6.0, hours)
+
+	err = loggingTime.SetHoursOn(period, time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), 1)
+	require.ErrorIs(t, err, ErrDateOutsidePeriod)
+	assert.Equal(t, 6.0, loggingTime.TotalHours())
+
+	byDate := loggingTime.HoursByDate(period)
+	assert.Len(t, byDate, 7)
+	assert.Equal(t, 6.0, byDate[NewDate(2021, 2, 16)])
+}
+
+func TestEditLoggingTimeHours(t *testing.T) {
+	loggingTime := EditLoggingTime{}
+	loggingTime.SetHours(time.Saturday, 3)
+	assert.Equal(t, 3.0, loggingTime.Day6Time)
+	assert.Equal(t, 3.0, loggingTime.Hours(time.Saturday))
+}
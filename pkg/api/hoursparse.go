@@ -0,0 +1,152 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidHours возвращается, если строку не удалось разобрать как количество часов
+var ErrInvalidHours = errors.New("invalid hours")
+
+// способ округления часов до шага HoursParser.Step
+type Rounding int
+
+const (
+	RoundNearest Rounding = iota
+	RoundUp
+	RoundDown
+)
+
+var roundingNames = map[Rounding]string{
+	RoundNearest: "nearest",
+	RoundUp:      "up",
+	RoundDown:    "down",
+}
+
+func (r Rounding) String() string {
+	if name, ok := roundingNames[r]; ok {
+		return name
+	}
+	return strconv.Itoa(int(r))
+}
+
+// ParseRounding возвращает способ округления по имени: nearest, up или down
+func ParseRounding(name string) (Rounding, error) {
+	for rounding, roundingName := range roundingNames {
+		if strings.EqualFold(name, roundingName) {
+			return rounding, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown rounding %q, expected nearest, up or down", name)
+}
+
+var (
+	decimalHours = regexp.MustCompile(`^(\d+)(?:[.,](\d+))?$`)
+	clockHours   = regexp.MustCompile(`^(\d+):(\d{2})$`)
+	unitHours    = regexp.MustCompile(`^(?:(\d+(?:[.,]\d+)?)\s*(?:h|ч))?\s*(?:(\d+)\s*(?:min|m|мин|м))?$`)
+)
+
+// разбор и округление часов. Нулевое значение разбирает часы без округления
+type HoursParser struct {
+	// шаг округления в часах, например 0.25 - до четверти часа; 0 - без округления
+	Step     float64
+	Rounding Rounding
+}
+
+// ParseHours разбирает часы без округления, см. HoursParser.Parse
+func ParseHours(s string) (float64, error) {
+	return HoursParser{}.Parse(s)
+}
+
+// Parse разбирает часы, записанные десятичным числом (7.5 или 7,5), временем (7:30)
+// или с единицами (7h30m, 7ч30м, 450m), и округляет их до шага Step.
+// Пустая строка - 0 часов. Запись вида 1.30, которую можно прочитать и как
+// 1.3 часа, и как 1 час 30 минут, считается ошибкой
+func (p HoursParser) Parse(s string) (float64, error) {
+	value := strings.ToLower(strings.TrimSpace(s))
+	if value == "" {
+		return 0, nil
+	}
+	hours, err := parseHours(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w %q: %s", ErrInvalidHours, s, err)
+	}
+	return p.Round(hours), nil
+}
+
+func parseHours(value string) (float64, error) {
+	if match := decimalHours.FindStringSubmatch(value); match != nil {
+		if fraction := match[2]; len(fraction) == 2 && fraction < "60" && !quarterFraction(fraction) {
+			return 0, fmt.Errorf(`ambiguous, write "%s:%s" for hours and minutes or "%sh" for decimal hours`, match[1], fraction, value)
+		}
+		return strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+	}
+	if match := clockHours.FindStringSubmatch(value); match != nil {
+		hours, _ := strconv.Atoi(match[1])
+		minutes, _ := strconv.Atoi(match[2])
+		if minutes >= 60 {
+			return 0, errors.New("minutes must be less than 60")
+		}
+		return float64(hours) + float64(minutes)/60, nil
+	}
+	if match := unitHours.FindStringSubmatch(value); match != nil && (match[1] != "" || match[2] != "") {
+		hours := 0.0
+		if match[1] != "" {
+			hours, _ = strconv.ParseFloat(strings.Replace(match[1], ",", ".", 1), 64)
+		}
+		if match[2] != "" {
+			minutes, _ := strconv.Atoi(match[2])
+			if match[1] != "" && minutes >= 60 {
+				return 0, errors.New("minutes must be less than 60")
+			}
+			hours += float64(minutes) / 60
+		}
+		return hours, nil
+	}
+	return 0, errors.New(`expected a number like 7.5, time like 7:30 or units like 7h30m`)
+}
+
+// дробная часть из двух цифр, однозначно означающая доли часа
+func quarterFraction(fraction string) bool {
+	return fraction == "00" || fraction == "25" || fraction == "50" || fraction == "75"
+}
+
+// Round округляет часы до шага Step
+func (p HoursParser) Round(hours float64) float64 {
+	if p.Step <= 0 {
+		return hours
+	}
+	steps := hours / p.Step
+	// погрешность деления не должна менять результат для значений, кратных шагу
+	if nearest := math.Round(steps); math.Abs(steps-nearest) < 1e-9 {
+		steps = nearest
+	}
+	switch p.Rounding {
+	case RoundUp:
+		steps = math.Ceil(steps)
+	case RoundDown:
+		steps = math.Floor(steps)
+	default:
+		steps = math.Round(steps)
+	}
+	// убирает погрешность умножения, например 3 * 0.1 = 0.30000000000000004
+	return math.Round(steps*p.Step*1e6) / 1e6
+}
+
+// FormatHours записывает часы в виде, который принимает Parse: 8h, 45m, 7h30m
+func FormatHours(hours float64) string {
+	minutes := int(math.Round(hours * 60))
+	switch {
+	case minutes == 0:
+		return "0"
+	case minutes%60 == 0:
+		return fmt.Sprintf("%dh", minutes/60)
+	case minutes < 60:
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh%dm", minutes/60, minutes%60)
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHours(t *testing.T) {
+	tests := map[string]float64{
+		"":        0,
+		"8":       8,
+		" 7.5 ":   7.5,
+		"7,5":     7.5,
+		"7.25":    7.25,
+		"1.3":     1.3,
+		"1:30":    1.5,
+		"0:45":    0.75,
+		"1h30m":   1.5,
+		"1H 30M":  1.5,
+		"1.5h":    1.5,
+		"1.30h":   1.3,
+		"90m":     1.5,
+		"90min":   1.5,
+		"2ч":      2,
+		"1ч15м":   1.25,
+		"45мин":   0.75,
+		"7.5h0m":  7.5,
+		"0h":      0,
+		"10":      10,
+		"12.00":   12,
+		"8.75":    8.75,
+		"120m":    2,
+		"1.333":   1.333,
+		"2.60":    2.6,
+		"0.5":     0.5,
+		"15m":     0.25,
+		"1:00":    1,
+		"3,75":    3.75,
+		"6.50":    6.5,
+		"4h45m":   4.75,
+		"2h 15 m": 2.25,
+	}
+	for input, expected := range tests {
+		hours, err := ParseHours(input)
+		require.NoError(t, err, input)
+		assert.InDelta(t, expected, hours, 1e-9, input)
+	}
+}
+
+func TestParseHoursErrors(t *testing.T) {
+	for _, input := range []string{"1.30", "1,45", "abc", "-1", "1:75", "1h90m", "1:3", "h", "8 часов", "1.5.5"} {
+		_, err := ParseHours(input)
+		assert.ErrorIs(t, err, ErrInvalidHours, input)
+	}
+	_, err := ParseHours("1.30")
+	assert.EqualError(t, err, `invalid hours "1.30": ambiguous, write "1:30" for hours and minutes or "1.30h" for decimal hours`)
+}
+
+func TestHoursParserRound(t *testing.T) {
+	nearest := HoursParser{Step: 0.25}
+	hours, err := nearest.Parse("1h20m")
+	require.NoError(t, err)
+	assert.Equal(t, 1.25, hours)
+	hours, err = nearest.Parse("1:10")
+	require.NoError(t, err)
+	assert.Equal(t, 1.25, hours)
+
+	up := HoursParser{Step: 0.25, Rounding: RoundUp}
+	assert.Equal(t, 1.5, up.Round(1.3))
+	assert.Equal(t, 1.25, up.Round(1.25))
+	assert.Equal(t, 0.3, HoursParser{Step: 0.1, Rounding: RoundUp}.Round(0.3))
+
+	down := HoursParser{Step: 0.5, Rounding: RoundDown}
+	assert.Equal(t, 7.0, down.Round(7.4))
+	assert.Equal(t, 1.3, HoursParser{}.Round(1.3))
+}
+
+func TestParseRounding(t *testing.T) {
+	rounding, err := ParseRounding("UP")
+	require.NoError(t, err)
+	assert.Equal(t, RoundUp, rounding)
+	assert.Equal(t, "down", RoundDown.String())
+	_, err = ParseRounding("half")
+	assert.Error(t, err)
+}
+
+func TestFormatHours(t *testing.T) {
+	tests := map[float64]string{0: "0", 8: "8h", 0.75: "45m", 7.5: "7h30m", 1.25: "1h15m"}
+	for hours, expected := range tests {
+		assert.Equal(t, expected, FormatHours(hours))
+		parsed, err := ParseHours(expected)
+		require.NoError(t, err)
+		assert.Equal(t, hours, parsed)
+	}
+}
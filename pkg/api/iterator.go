@@ -0,0 +1,172 @@
+package api
+
+import "context"
+
+// размер страницы, который используется, если в опциях он не передан
+const defaultPageSize int = 5
+
+// ScheduleIterator последовательно обходит страницы списка расписаний.
+// Очередная страница запрашивается только когда закончилась предыдущая,
+// поэтому обход можно прервать в любой момент.
+//
+//	it := api.NewScheduleIterator(client, nil)
+//	for it.Next() {
+//		fmt.Println(it.Schedule().Id)
+//	}
+//	if err := it.Err(); err != nil {
+//		log.Fatalln(err)
+//	}
+type ScheduleIterator struct {
+	ctx      context.Context
+	client   API
+	options  OptionsS
+	page     []*Schedule
+	index    int
+	current  *Schedule
+	lastPage bool
+	err      error
+}
+
+func NewScheduleIterator(client API, options *OptionsS) *ScheduleIterator {
+	return NewScheduleIteratorContext(context.Background(), client, options)
+}
+
+func NewScheduleIteratorContext(ctx context.Context, client API, options *OptionsS) *ScheduleIterator {
+	it := &ScheduleIterator{
+		ctx:    ctx,
+		client: client,
+	}
+	if options != nil {
+		it.options = *options
+	}
+	if it.options.Size == 0 {
+		it.options.Size = defaultPageSize
+	}
+	return it
+}
+
+// Next переходит к следующему расписанию. Возвращает false, когда расписания
+// закончились или произошла ошибка.
+func (it *ScheduleIterator) Next() bool {
+	for it.index >= len(it.page) {
+		if it.lastPage || it.err != nil {
+			it.current = nil
+			return false
+		}
+		it.page, it.err = it.client.SchedulesContext(it.ctx, &it.options)
+		it.index = 0
+		if len(it.page) < it.options.Size {
+			it.lastPage = true
+		}
+		it.options.Page++
+	}
+	it.current = it.page[it.index]
+	it.index++
+	return true
+}
+
+// Schedule возвращает текущее расписание
+func (it *ScheduleIterator) Schedule() *Schedule {
+	return it.current
+}
+
+// Err возвращает ошибку, на которой остановился обход
+func (it *ScheduleIterator) Err() error {
+	return it.err
+}
+
+// LoggingTimeIterator последовательно обходит страницы списка временных затрат расписания
+type LoggingTimeIterator struct {
+	ctx        context.Context
+	client     API
+	scheduleId ScheduleId
+	options    OptionsLT
+	page       []*LoggingTime
+	index      int
+	current    *LoggingTime
+	lastPage   bool
+	err        error
+}
+
+func NewLoggingTimeIterator(client API, scheduleId ScheduleId, options *OptionsLT) *LoggingTimeIterator {
+	return NewLoggingTimeIteratorContext(context.Background(), client, scheduleId, options)
+}
+
+func NewLoggingTimeIteratorContext(ctx context.Context, client API, scheduleId ScheduleId, options *OptionsLT) *LoggingTimeIterator {
+	it := &LoggingTimeIterator{
+		ctx:        ctx,
+		client:     client,
+		scheduleId: scheduleId,
+	}
+	if options != nil {
+		it.options = *options
+	}
+	if it.options.Size == 0 {
+		it.options.Size = defaultPageSize
+	}
+	return it
+}
+
+// Next переходит к следующей временной затрате. Возвращает false, когда
+// временные затраты закончились или произошла ошибка.
+func (it *LoggingTimeIterator) Next() bool {
+	for it.index >= len(it.page) {
+		if it.lastPage || it.err != nil {
+			it.current = nil
+			return false
+		}
+		it.page, it.err = it.client.LoggingTimeListContext(it.ctx, it.scheduleId, &it.options)
+		it.index = 0
+		if len(it.page) < it.options.Size {
+			it.lastPage = true
+		}
+		it.options.Page++
+	}
+	it.current = it.page[it.index]
+	it.index++
+	return true
+}
+
+// LoggingTime возвращает текущую временную затрату
+func (it *LoggingTimeIterator) LoggingTime() *LoggingTime {
+	return it.current
+}
+
+// Err возвращает ошибку, на которой остановился обход
+func (it *LoggingTimeIterator) Err() error {
+	return it.err
+}
+
+// AllSchedules возвращает расписания со всех страниц, начиная с options.Page
+func AllSchedules(client API, options *OptionsS) ([]*Schedule, error) {
+	return AllSchedulesContext(context.Background(), client, options)
+}
+
+func AllSchedulesContext(ctx context.Context, client API, options *OptionsS) ([]*Schedule, error) {
+	schedules := []*Schedule{}
+	it := NewScheduleIteratorContext(ctx, client, options)
+	for it.Next() {
+		schedules = append(schedules, it.Schedule())
+	}
+	if it.Err() != nil {
+		return nil, it.Err()
+	}
+	return schedules, nil
+}
+
+// AllLoggingTimes возвращает временные затраты расписания со всех страниц, начиная с options.Page
+func AllLoggingTimes(client API, scheduleId ScheduleId, options *OptionsLT) ([]*LoggingTime, error) {
+	return AllLoggingTimesContext(context.Background(), client, scheduleId, options)
+}
+
+func AllLoggingTimesContext(ctx context.Context, client API, scheduleId ScheduleId, options *OptionsLT) ([]*LoggingTime, error) {
+	loggingTimes := []*LoggingTime{}
+	it := NewLoggingTimeIteratorContext(ctx, client, scheduleId, options)
+	for it.Next() {
+		loggingTimes = append(loggingTimes, it.LoggingTime())
+	}
+	if it.Err() != nil {
+		return nil, it.Err()
+	}
+	return loggingTimes, nil
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// отдаёт total элементов постранично, учитывая параметры page и size
+type pagingHttpClient struct {
+	total    int
+	failPage int
+	requests int
+}
+
+func (m *pagingHttpClient) Do(req *http.Request) (*http.Response, error) {
+	m.requests++
+	page, _ := strconv.Atoi(req.URL.Query().Get("page"))
+	size, _ := strconv.Atoi(req.URL.Query().Get("size"))
+	if m.failPage != 0 && page == m.failPage {
+		return nil, errors.New("error from doHTTP")
+	}
+	items := []map[string]int{}
+	for i := page * size; i < (page+1)*size && i < m.total; i++ {
+		items = append(items, map[string]int{"id": i + 1})
+	}
+	respB, _ := json.Marshal(items)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(bytes.NewReader(respB)),
+	}, nil
+}
+
+func newPagingClient(total int) (*Client, *pagingHttpClient) {
+	httpClient := &pagingHttpClient{total: total}
+	return &Client{
+		BaseURL:      BaseURL,
+		AccessToken:  "fake_access_token",
+		RefreshToken: "fake_refresh_token",
+		HttpClient:   httpClient,
+	}, httpClient
+}
+
+func TestAllSchedules(t *testing.T) {
+	client, httpClient := newPagingClient(12)
+	schedules, err := AllSchedules(client, &OptionsS{Size: 5})
+	require.NoError(t, err)
+	require.Len(t, schedules, 12)
+	assert.Equal(t, 1, schedules[0].Id)
+	assert.Equal(t, 12, schedules[11].Id)
+	assert.Equal(t, 3, httpClient.requests)
+}
+
+func TestAllSchedulesExactPages(t *testing.T) {
+	client, httpClient := newPagingClient(10)
+	schedules, err := AllSchedules(client, nil)
+	require.NoError(t, err)
+	assert.Len(t, schedules, 10)
+	// последняя страница пустая
+	assert.Equal(t, 3, httpClient.requests)
+}
+
+func TestAllSchedulesError(t *testing.T) {
+	client, httpClient := newPagingClient(12)
+	httpClient.failPage = 1
+	schedules, err := AllSchedules(client, nil)
+	require.Error(t, err)
+	assert.Nil(t, schedules)
+}
+
+func TestScheduleIteratorEarlyStop(t *testing.T) {
+	client, httpClient := newPagingClient(100)
+	it := NewScheduleIterator(client, &OptionsS{Size: 5})
+	count := 0
+	for it.Next() {
+		count++
+		if it.Schedule().Id == 7 {
+			break
+		}
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, 7, count)
+	assert.Equal(t, 2, httpClient.requests)
+}
+
+func TestAllLoggingTimes(t *testing.T) {
+	client, _ := newPagingClient(7)
+	loggingTimes, err := AllLoggingTimes(client, 777, &OptionsLT{Size: 3})
+	require.NoError(t, err)
+	require.Len(t, loggingTimes, 7)
+	for _, loggingTime := range loggingTimes {
+		assert.Equal(t, ScheduleId(777), loggingTime.scheduleId)
+		assert.Equal(t, client, loggingTime.client)
+	}
+}
+
+func TestLoggingTimeIteratorStartPage(t *testing.T) {
+	client, httpClient := newPagingClient(7)
+	it := NewLoggingTimeIterator(client, 777, &OptionsLT{Page: 1, Size: 3})
+	ids := []string{}
+	for it.Next() {
+		ids = append(ids, strconv.Itoa(it.LoggingTime().Id))
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, "4,5,6,7", strings.Join(ids, ","))
+	assert.Equal(t, 2, httpClient.requests)
+	assert.False(t, it.Next())
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"suftsdk/pkg/logging"
+)
+
+// журнал клиента, по умолчанию записи не пишутся
+func (c *Client) logger() logging.Logger {
+	if c.Logger == nil {
+		return logging.Nop
+	}
+	return c.Logger
+}
+
+func requestFields(httpMethod string, URN string) []logging.Field {
+	fields := []logging.Field{{Key: logging.FieldMethod, Value: httpMethod}}
+	if URN != "" {
+		fields = append(fields, logging.Field{Key: logging.FieldURN, Value: URN})
+	}
+	return fields
+}
+
+// запрос к серверу: статус и длительность, при сетевой ошибке - ошибка
+func (c *Client) logRequest(httpMethod string, URN string, resp *http.Response, err error, duration time.Duration) {
+	fields := append(requestFields(httpMethod, URN), logging.Field{Key: logging.FieldDuration, Value: duration})
+	if err != nil {
+		c.logger().Log(logging.Warn, "request failed", append(fields, logging.Field{Key: logging.FieldError, Value: err})...)
+		return
+	}
+	c.logger().Log(logging.Debug, "request", append(fields, logging.Field{Key: logging.FieldStatus, Value: resp.StatusCode})...)
+}
+
+// неудачная попытка, после которой запрос будет повторён через delay
+func (c *Client) logRetry(httpMethod string, URN string, attempt int, resp *http.Response, err error, delay time.Duration) {
+	fields := append(requestFields(httpMethod, URN),
+		logging.Field{Key: logging.FieldAttempt, Value: attempt},
+		logging.Field{Key: "delay", Value: delay},
+	)
+	if err != nil {
+		fields = append(fields, logging.Field{Key: logging.FieldError, Value: err})
+	} else {
+		fields = append(fields, logging.Field{Key: logging.FieldStatus, Value: resp.StatusCode})
+	}
+	c.logger().Log(logging.Warn, "retrying request", fields...)
+}
+
+func (c *Client) logError(httpMethod string, URN string, msg string, err error) {
+	c.logger().Log(logging.Error, msg, append(requestFields(httpMethod, URN), logging.Field{Key: logging.FieldError, Value: err})...)
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"suftsdk/pkg/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type logEntry struct {
+	level  logging.Level
+	msg    string
+	fields map[string]interface{}
+}
+
+// запоминает записи журнала
+type recordingLogger struct {
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+func (l *recordingLogger) Log(level logging.Level, msg string, fields ...logging.Field) {
+	entry := logEntry{level: level, msg: msg, fields: map[string]interface{}{}}
+	for _, field := range fields {
+		entry.fields[field.Key] = field.Value
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, entry)
+}
+
+func TestLoggerRequestsAndRetries(t *testing.T) {
+	client, _ := newRetryClient(
+		statusResp(http.StatusServiceUnavailable, "Service Unavailable"),
+		jsonResp(http.StatusOK, fakeSchedule1),
+	)
+	logger := &recordingLogger{}
+	client.Logger = logger
+	_, err := client.DetailSchedule(777)
+	require.NoError(t, err)
+
+	require.Len(t, logger.entries, 3)
+	assert.Equal(t, logging.Debug, logger.entries[0].level)
+	assert.Equal(t, "request", logger.entries[0].msg)
+	assert.Equal(t, http.MethodGet, logger.entries[0].fields[logging.FieldMethod])
+	assert.Equal(t, "api/v1/schedules/777", logger.entries[0].fields[logging.FieldURN])
+	assert.Equal(t, http.StatusServiceUnavailable, logger.entries[0].fields[logging.FieldStatus])
+	assert.IsType(t, time.Duration(0), logger.entries[0].fields[logging.FieldDuration])
+
+	assert.Equal(t, logging.Warn, logger.entries[1].level)
+	assert.Equal(t, "retrying request", logger.entries[1].msg)
+	assert.Equal(t, 1, logger.entries[1].fields[logging.FieldAttempt])
+	assert.Equal(t, http.StatusServiceUnavailable, logger.entries[1].fields[logging.FieldStatus])
+
+	assert.Equal(t, http.StatusOK, logger.entries[2].fields[logging.FieldStatus])
+}
+
+func TestLoggerErrors(t *testing.T) {
+	client, _ := newRetryClient(statusResp(http.StatusOK, "not json"))
+	client.RetryPolicy = nil
+	logger := &recordingLogger{}
+	client.Logger = logger
+	_, err := client.DetailSchedule(777)
+	require.Error(t, err)
+
+	require.Len(t, logger.entries, 2)
+	assert.Equal(t, logging.Error, logger.entries[1].level)
+	assert.Equal(t, "DetailSchedule: unable to unmarshal response body", logger.entries[1].msg)
+	assert.Equal(t, "api/v1/schedules/777", logger.entries[1].fields[logging.FieldURN])
+	assert.Equal(t, err, logger.entries[1].fields[logging.FieldError])
+}
+
+func TestLoggerSilentByDefault(t *testing.T) {
+	var buf bytes.Buffer
+	output := log.Writer()
+	log.SetOutput(&buf)
+	defer log.SetOutput(output)
+
+	client, _ := newRetryClient(errorResp, errorResp, errorResp)
+	_, err := client.DetailSchedule(777)
+	require.Error(t, err)
+	assert.Empty(t, buf.String())
+}
@@ -1,8 +1,10 @@
 package api
 
+import "context"
+
 type LoggingTime struct {
 	scheduleId           ScheduleId
-	client               *Client
+	client               API
 	AdminEmployee        Employee   `json:"adminEmployee"`
 	CommentAdminEmployee string     `json:"commentAdminEmployee"`
 	CommentEmployee      string     `json:"commentEmployee"`
@@ -46,14 +48,91 @@ type EditLoggingTime struct {
 	Day6Time             float64    `json:"day6Time"`
 	Day7Time             float64    `json:"day7Time"`
 	ProjectId            int        `json:"projectId"`
-	StatusCode           StatusCode `json:"statusCode"`
+	StatusCode           StatusCode `json:"statusCode,omitempty"`
 	Task                 string     `json:"task"`
 	WorkKindId           int        `json:"workKindId"`
 }
 
+// NewEditLoggingTime возвращает изменения, заполненные текущими значениями временной затраты.
+// Статус не заполняется, чтобы при сохранении его не перезаписать
+func NewEditLoggingTime(l *LoggingTime) *EditLoggingTime {
+	return &EditLoggingTime{
+		CommentAdminEmployee: l.CommentAdminEmployee,
+		CommentEmployee:      l.CommentEmployee,
+		Day1Time:             l.Day1Time,
+		Day2Time:             l.Day2Time,
+		Day3Time:             l.Day3Time,
+		Day4Time:             l.Day4Time,
+		Day5Time:             l.Day5Time,
+		Day6Time:             l.Day6Time,
+		Day7Time:             l.Day7Time,
+		ProjectId:            l.ProjectId,
+		Task:                 l.Task,
+		WorkKindId:           l.WorkKindId,
+	}
+}
+
+// NewAddLoggingTime возвращает новую временную затрату с теми же проектом,
+// видом работ, задачей, часами и комментарием сотрудника
+func NewAddLoggingTime(l *LoggingTime) *AddLoggingTime {
+	return &AddLoggingTime{
+		CommentEmployee: l.CommentEmployee,
+		Day1Time:        l.Day1Time,
+		Day2Time:        l.Day2Time,
+		Day3Time:        l.Day3Time,
+		Day4Time:        l.Day4Time,
+		Day5Time:        l.Day5Time,
+		Day6Time:        l.Day6Time,
+		Day7Time:        l.Day7Time,
+		ProjectId:       l.ProjectId,
+		Task:            l.Task,
+		WorkKindId:      l.WorkKindId,
+	}
+}
+
+// SetClient задаёт клиент и расписание, через которые методы временной затраты
+// отправляют запросы. Клиенты пакета api задают их сами, метод нужен другим реализациям API
+func (l *LoggingTime) SetClient(client API, scheduleId ScheduleId) {
+	l.client = client
+	l.scheduleId = scheduleId
+}
+
+// CanEdit сообщает, можно ли изменить или удалить временную затрату
+func (l *LoggingTime) CanEdit() bool {
+	return l.StatusCode.CanEdit()
+}
+
+// CanApprove сообщает, можно ли утвердить или отклонить временную затрату
+func (l *LoggingTime) CanApprove() bool {
+	return l.StatusCode.CanApprove()
+}
+
+func (l *LoggingTime) Update(loggingTime *EditLoggingTime) (*LoggingTime, error) {
+	return l.UpdateContext(context.Background(), loggingTime)
+}
+
+func (l *LoggingTime) UpdateContext(ctx context.Context, loggingTime *EditLoggingTime) (*LoggingTime, error) {
+	if err := l.StatusCode.Check(ActionEdit); err != nil {
+		return nil, err
+	}
+	loggingTimeId := LoggingTimeId(l.Id)
+	loggingTimeResp, err := l.client.UpdateLoggingTimeContext(ctx, l.scheduleId, loggingTimeId, loggingTime)
+	if err != nil {
+		return nil, err
+	}
+	return loggingTimeResp, nil
+}
+
 func (l *LoggingTime) ApproveLoggingTime(comment string) (*LoggingTime, error) {
+	return l.ApproveLoggingTimeContext(context.Background(), comment)
+}
+
+func (l *LoggingTime) ApproveLoggingTimeContext(ctx context.Context, comment string) (*LoggingTime, error) {
+	if err := l.StatusCode.Check(ActionApprove); err != nil {
+		return nil, err
+	}
 	loggingTimeId := LoggingTimeId(l.Id)
-	loggingTime, err := l.client.ApproveLoggingTime(l.scheduleId, loggingTimeId, comment)
+	loggingTime, err := l.client.ApproveLoggingTimeContext(ctx, l.scheduleId, loggingTimeId, comment)
 	if err != nil {
 		return nil, err
 	}
@@ -61,8 +140,15 @@ func (l *LoggingTime) ApproveLoggingTime(comment string) (*LoggingTime, error) {
 }
 
 func (l *LoggingTime) DeclineLoggingTime(comment string) (*LoggingTime, error) {
+	return l.DeclineLoggingTimeContext(context.Background(), comment)
+}
+
+func (l *LoggingTime) DeclineLoggingTimeContext(ctx context.Context, comment string) (*LoggingTime, error) {
+	if err := l.StatusCode.Check(ActionDecline); err != nil {
+		return nil, err
+	}
 	loggingTimeId := LoggingTimeId(l.Id)
-	loggingTime, err := l.client.DeclineLoggingTime(l.scheduleId, loggingTimeId, comment)
+	loggingTime, err := l.client.DeclineLoggingTimeContext(ctx, l.scheduleId, loggingTimeId, comment)
 	if err != nil {
 		return nil, err
 	}
@@ -70,8 +156,15 @@ func (l *LoggingTime) DeclineLoggingTime(comment string) (*LoggingTime, error) {
 }
 
 func (l *LoggingTime) DeleteLoggingTime() (err error) {
+	return l.DeleteLoggingTimeContext(context.Background())
+}
+
+func (l *LoggingTime) DeleteLoggingTimeContext(ctx context.Context) (err error) {
+	if err := l.StatusCode.Check(ActionEdit); err != nil {
+		return err
+	}
 	loggingTimeId := LoggingTimeId(l.Id)
-	err = l.client.DeleteLoggingTime(l.scheduleId, loggingTimeId)
+	err = l.client.DeleteLoggingTimeContext(ctx, l.scheduleId, loggingTimeId)
 	if err != nil {
 		return err
 	}
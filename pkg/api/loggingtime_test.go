@@ -1 +1,56 @@
 package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEditLoggingTime(t *testing.T) {
+	loggingTime := &LoggingTime{
+		CommentAdminEmployee: "admin",
+		CommentEmployee:      "employee",
+		Day1Time:             1,
+		Day7Time:             7,
+		ProjectId:            10,
+		StatusCode:           Declined,
+		Task:                 "task",
+		WorkKindId:           20,
+	}
+	edit := NewEditLoggingTime(loggingTime)
+	assert.Equal(t, &EditLoggingTime{
+		CommentAdminEmployee: "admin",
+		CommentEmployee:      "employee",
+		Day1Time:             1,
+		Day7Time:             7,
+		ProjectId:            10,
+		Task:                 "task",
+		WorkKindId:           20,
+	}, edit)
+
+	editB, err := json.Marshal(edit)
+	require.NoError(t, err)
+	assert.NotContains(t, string(editB), "statusCode")
+}
+
+func TestLoggingTimeUpdate(t *testing.T) {
+	client, httpClient := newRetryClient(jsonResp(http.StatusOK, fakeLoggingTime2))
+	loggingTime := &LoggingTime{Id: 5, scheduleId: 777, client: client}
+	edit := &EditLoggingTime{Day1Time: 8, Task: "task"}
+	loggingTimeResp, err := loggingTime.Update(edit)
+	require.NoError(t, err)
+	assert.Equal(t, fakeLoggingTime2.Task, loggingTimeResp.Task)
+
+	require.Len(t, httpClient.requests, 1)
+	req := httpClient.requests[0]
+	assert.Equal(t, http.MethodPatch, req.Method)
+	assert.Equal(t, "/tools/suft/api/v1/api/v1/schedules/777/logging-times/5", req.URL.Path)
+	reqB, err := ioutil.ReadAll(req.Body)
+	require.NoError(t, err)
+	expected, _ := json.Marshal(edit)
+	assert.Equal(t, string(expected), string(reqB))
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// заголовок, в котором RequestID передаёт id запроса
+const RequestIDHeader = "X-Request-Id"
+
+// Middleware оборачивает HttpClient, чтобы изменить запрос, ответ или выполнить
+// действие вокруг запроса. Middleware клиента применяются ко всем его запросам,
+// в том числе к аутентификации, обновлению токенов и повторам
+type Middleware func(next HttpClient) HttpClient
+
+// HttpClientFunc позволяет использовать функцию как HttpClient
+type HttpClientFunc func(req *http.Request) (*http.Response, error)
+
+func (f HttpClientFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Chain оборачивает client цепочкой middlewares. Первая middleware в списке
+// получает запрос первой, а ответ - последней
+func Chain(client HttpClient, middlewares ...Middleware) HttpClient {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		client = middlewares[i](client)
+	}
+	return client
+}
+
+// UserAgent задаёт заголовок User-Agent всех запросов
+func UserAgent(userAgent string) Middleware {
+	return func(next HttpClient) HttpClient {
+		return HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("User-Agent", userAgent)
+			return next.Do(req)
+		})
+	}
+}
+
+type requestIDKey struct{}
+
+// WithRequestID сохраняет в контексте id, который RequestID передаст в запросах
+// с этим контекстом, чтобы связать их с запросом или задачей вызывающего кода
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext возвращает id, сохранённый WithRequestID
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(requestIDKey{}).(string)
+	return requestID, ok && requestID != ""
+}
+
+// RequestID передаёт в заголовке X-Request-Id id из контекста запроса (WithRequestID),
+// а если его нет - случайный id. Заголовок, заданный раньше в цепочке, не изменяется
+func RequestID() Middleware {
+	return func(next HttpClient) HttpClient {
+		return HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get(RequestIDHeader) == "" {
+				requestID, ok := RequestIDFromContext(req.Context())
+				if !ok {
+					requestID = newRequestID()
+				}
+				req.Header.Set(RequestIDHeader, requestID)
+			}
+			return next.Do(req)
+		})
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// Timing вызывает observe после каждого запроса с его длительностью.
+// resp равен nil, если запрос завершился ошибкой err
+func Timing(observe func(req *http.Request, resp *http.Response, err error, duration time.Duration)) Middleware {
+	return func(next HttpClient) HttpClient {
+		return HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.Do(req)
+			observe(req, resp, err, time.Since(start))
+			return resp, err
+		})
+	}
+}
+
+var (
+	tokenCookieRe = regexp.MustCompile(`((?:Access|Refresh)-token=)[^;\s]*`)
+	passwordRe    = regexp.MustCompile(`("password"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+)
+
+// DebugDump пишет в w запросы и ответы целиком. Значения cookie Access-token
+// и Refresh-token и пароль в теле запроса аутентификации заменяются на REDACTED
+func DebugDump(w io.Writer) Middleware {
+	mu := &sync.Mutex{}
+	write := func(dump []byte) {
+		dump = tokenCookieRe.ReplaceAll(dump, []byte("${1}REDACTED"))
+		dump = passwordRe.ReplaceAll(dump, []byte(`${1}"REDACTED"`))
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = w.Write(append(dump, "\n\n"...))
+	}
+	return func(next HttpClient) HttpClient {
+		return HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+			if dump, err := httputil.DumpRequestOut(req, true); err == nil {
+				write(dump)
+			}
+			resp, err := next.Do(req)
+			if err != nil {
+				write([]byte(fmt.Sprintf("%s %s: %v", req.Method, req.URL, err)))
+				return nil, err
+			}
+			if dump, err := httputil.DumpResponse(resp, true); err == nil {
+				write(dump)
+			}
+			return resp, nil
+		})
+	}
+}
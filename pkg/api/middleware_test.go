@@ -0,0 +1,125 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tokensResp(req *http.Request) (*http.Response, error) {
+	header := http.Header{}
+	header.Add("Set-Cookie", "Access-token=new_access_token")
+	header.Add("Set-Cookie", "Refresh-token=new_refresh_token")
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     header,
+		Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+	}, nil
+}
+
+func TestChainOrder(t *testing.T) {
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next HttpClient) HttpClient {
+			return HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+" request")
+				resp, err := next.Do(req)
+				calls = append(calls, name+" response")
+				return resp, err
+			})
+		}
+	}
+	client := Chain(&scriptedHttpClient{responses: []func(*http.Request) (*http.Response, error){statusResp(http.StatusOK, "")}},
+		trace("a"), trace("b"))
+	req, err := http.NewRequest(http.MethodGet, BaseURL, nil)
+	require.NoError(t, err)
+	_, err = client.Do(req)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a request", "b request", "b response", "a response"}, calls)
+}
+
+func TestMiddlewaresOnClient(t *testing.T) {
+	httpClient := &scriptedHttpClient{responses: []func(*http.Request) (*http.Response, error){
+		tokensResp,
+		jsonResp(http.StatusOK, fakeSchedule1),
+		jsonResp(http.StatusOK, fakeSchedule1),
+	}}
+	var durations []time.Duration
+	client, err := NewClient("demo@example.com", "demo", &OptionsNC{
+		HttpClient: httpClient,
+		Middlewares: []Middleware{
+			UserAgent("suftsdk-test"),
+			RequestID(),
+			Timing(func(req *http.Request, resp *http.Response, err error, duration time.Duration) {
+				durations = append(durations, duration)
+			}),
+		},
+	})
+	require.NoError(t, err)
+	_, err = client.DetailScheduleContext(WithRequestID(context.Background(), "job-42"), 777)
+	require.NoError(t, err)
+	_, err = client.DetailSchedule(777)
+	require.NoError(t, err)
+
+	require.Len(t, httpClient.requests, 3)
+	for _, req := range httpClient.requests {
+		assert.Equal(t, "suftsdk-test", req.Header.Get("User-Agent"))
+		assert.NotEmpty(t, req.Header.Get(RequestIDHeader))
+	}
+	assert.Equal(t, "job-42", httpClient.requests[1].Header.Get(RequestIDHeader))
+	assert.NotEqual(t, httpClient.requests[0].Header.Get(RequestIDHeader), httpClient.requests[2].Header.Get(RequestIDHeader))
+	assert.Len(t, durations, 3)
+}
+
+func TestMiddlewaresOnRefresh(t *testing.T) {
+	httpClient := &scriptedHttpClient{responses: []func(*http.Request) (*http.Response, error){
+		statusResp(http.StatusUnauthorized, ""),
+		tokensResp,
+		jsonResp(http.StatusOK, fakeSchedule1),
+	}}
+	client := &Client{
+		BaseURL:      BaseURL,
+		AccessToken:  "old_access_token",
+		RefreshToken: "old_refresh_token",
+		HttpClient:   httpClient,
+		Middlewares:  []Middleware{UserAgent("suftsdk-test")},
+	}
+	_, err := client.DetailSchedule(777)
+	require.NoError(t, err)
+	require.Len(t, httpClient.requests, 3)
+	assert.Equal(t, "/tools/suft/api/v1/security/refresh-token", httpClient.requests[1].URL.Path)
+	assert.Equal(t, "suftsdk-test", httpClient.requests[1].Header.Get("User-Agent"))
+}
+
+func TestDebugDumpRedacts(t *testing.T) {
+	httpClient := &scriptedHttpClient{responses: []func(*http.Request) (*http.Response, error){
+		tokensResp,
+		jsonResp(http.StatusOK, fakeSchedule1),
+	}}
+	var dump bytes.Buffer
+	client, err := NewClient("demo@example.com", "s3cret", &OptionsNC{
+		HttpClient:  httpClient,
+		Middlewares: []Middleware{DebugDump(&dump)},
+	})
+	require.NoError(t, err)
+	_, err = client.DetailSchedule(777)
+	require.NoError(t, err)
+
+	out := dump.String()
+	assert.Contains(t, out, "POST /tools/suft/api/v1/security/authenticate")
+	assert.Contains(t, out, `"username":"demo@example.com"`)
+	assert.Contains(t, out, `"password":"REDACTED"`)
+	assert.Contains(t, out, "Set-Cookie: Access-token=REDACTED")
+	assert.Contains(t, out, "Set-Cookie: Refresh-token=REDACTED")
+	assert.Contains(t, out, "Cookie: Access-token=REDACTED")
+	assert.Contains(t, out, "GET /tools/suft/api/v1/api/v1/schedules/777")
+	assert.NotContains(t, out, "s3cret")
+	assert.NotContains(t, out, "new_access_token")
+	assert.NotContains(t, out, "new_refresh_token")
+}
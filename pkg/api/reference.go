@@ -0,0 +1,177 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	ProjectsURN  string = "api/v1/projects"
+	WorkKindsURN string = "api/v1/work-kinds"
+	PeriodsURN   string = "api/v1/periods"
+
+	dateLayout string = "2006-01-02"
+)
+
+type Project struct {
+	Id   int    `json:"id"`
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+type WorkKind struct {
+	Id   int    `json:"id"`
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// опции для метода Projects. Фильтрует сервер, поэтому Page и Size
+// относятся к уже отфильтрованному списку
+type OptionsP struct {
+	Page int `json:"page"`
+	Size int `json:"size"`
+	// подстрока кода или названия проекта, регистр не учитывается
+	Search string `json:"search"`
+}
+
+// опции для метода WorkKinds
+type OptionsWK struct {
+	// подстрока кода или названия вида работ, регистр не учитывается
+	Search string `json:"search"`
+}
+
+// опции для метода Periods. Сервер возвращает периоды, пересекающиеся
+// с интервалом [StartDate, EndDate]; нулевая дата не ограничивает интервал
+type OptionsPD struct {
+	Page      int       `json:"page"`
+	Size      int       `json:"size"`
+	StartDate time.Time `json:"startDate"`
+	EndDate   time.Time `json:"endDate"`
+}
+
+func (c *Client) Projects(options *OptionsP) ([]*Project, error) {
+	return c.ProjectsContext(context.Background(), options)
+}
+
+func (c *Client) ProjectsContext(ctx context.Context, options *OptionsP) ([]*Project, error) {
+	page := 0
+	size := defaultPageSize
+	search := ""
+	if options != nil {
+		page = options.Page
+		if options.Size != 0 {
+			size = options.Size
+		}
+		search = options.Search
+	}
+	URN := fmt.Sprint(ProjectsURN, "?page=", page, "&size=", size)
+	if search != "" {
+		URN = fmt.Sprint(URN, "&search=", url.QueryEscape(search))
+	}
+	resp, err := c.doHTTP(ctx, http.MethodGet, URN, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respB, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.logError(http.MethodGet, URN, "Projects: unable to read response body", err)
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(http.MethodGet, URN, resp.StatusCode, respB)
+	}
+	projects := []*Project{}
+	err = json.Unmarshal(respB, &projects)
+	if err != nil {
+		c.logError(http.MethodGet, URN, "Projects: unable to unmarshal response body", err)
+		return nil, err
+	}
+	return projects, nil
+}
+
+func (c *Client) WorkKinds(options *OptionsWK) ([]*WorkKind, error) {
+	return c.WorkKindsContext(context.Background(), options)
+}
+
+func (c *Client) WorkKindsContext(ctx context.Context, options *OptionsWK) ([]*WorkKind, error) {
+	search := ""
+	if options != nil {
+		search = options.Search
+	}
+	URN := WorkKindsURN
+	if search != "" {
+		URN = fmt.Sprint(URN, "?search=", url.QueryEscape(search))
+	}
+	resp, err := c.doHTTP(ctx, http.MethodGet, URN, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respB, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.logError(http.MethodGet, URN, "WorkKinds: unable to read response body", err)
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(http.MethodGet, URN, resp.StatusCode, respB)
+	}
+	workKinds := []*WorkKind{}
+	err = json.Unmarshal(respB, &workKinds)
+	if err != nil {
+		c.logError(http.MethodGet, URN, "WorkKinds: unable to unmarshal response body", err)
+		return nil, err
+	}
+	return workKinds, nil
+}
+
+func (c *Client) Periods(options *OptionsPD) ([]*Period, error) {
+	return c.PeriodsContext(context.Background(), options)
+}
+
+func (c *Client) PeriodsContext(ctx context.Context, options *OptionsPD) ([]*Period, error) {
+	page := 0
+	size := defaultPageSize
+	var startDate, endDate time.Time
+	if options != nil {
+		page = options.Page
+		if options.Size != 0 {
+			size = options.Size
+		}
+		startDate = options.StartDate
+		endDate = options.EndDate
+	}
+	URN := fmt.Sprint(PeriodsURN, "?page=", page, "&size=", size)
+	if !startDate.IsZero() {
+		URN = fmt.Sprint(URN, "&startDate=", startDate.Format(dateLayout))
+	}
+	if !endDate.IsZero() {
+		URN = fmt.Sprint(URN, "&endDate=", endDate.Format(dateLayout))
+	}
+	resp, err := c.doHTTP(ctx, http.MethodGet, URN, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respB, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.logError(http.MethodGet, URN, "Periods: unable to read response body", err)
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(http.MethodGet, URN, resp.StatusCode, respB)
+	}
+	periods := []*Period{}
+	err = json.Unmarshal(respB, &periods)
+	if err != nil {
+		c.logError(http.MethodGet, URN, "Periods: unable to unmarshal response body", err)
+		return nil, err
+	}
+
+	return periods, nil
+}
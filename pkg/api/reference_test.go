@@ -0,0 +1,77 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectsSuccess(t *testing.T) {
+	client, httpClient := newRetryClient(jsonResp(http.StatusOK, []Project{
+		{Id: 1, Code: "SUFT", Name: "Система учёта"},
+	}))
+	projects, err := client.Projects(&OptionsP{Page: 1, Size: 10, Search: "учёт"})
+	require.NoError(t, err)
+	assert.Equal(t, []*Project{{Id: 1, Code: "SUFT", Name: "Система учёта"}}, projects)
+
+	require.Len(t, httpClient.requests, 1)
+	query := httpClient.requests[0].URL.Query()
+	assert.Equal(t, "1", query.Get("page"))
+	assert.Equal(t, "10", query.Get("size"))
+	assert.Equal(t, "учёт", query.Get("search"))
+}
+
+func TestProjectsError(t *testing.T) {
+	client, _ := newRetryClient(statusResp(http.StatusForbidden, "Forbidden"))
+	projects, err := client.Projects(nil)
+	require.ErrorIs(t, err, ErrForbidden)
+	assert.Nil(t, projects)
+}
+
+func TestWorkKindsSuccess(t *testing.T) {
+	client, httpClient := newRetryClient(jsonResp(http.StatusOK, []WorkKind{
+		{Id: 22, Name: "Тестирование"},
+	}))
+	workKinds, err := client.WorkKinds(&OptionsWK{Search: "ТЕСТ"})
+	require.NoError(t, err)
+	assert.Equal(t, []*WorkKind{{Id: 22, Name: "Тестирование"}}, workKinds)
+	assert.Equal(t, "/tools/suft/api/v1/api/v1/work-kinds", httpClient.requests[0].URL.Path)
+	assert.Equal(t, "ТЕСТ", httpClient.requests[0].URL.Query().Get("search"))
+}
+
+func TestWorkKindsWithoutSearch(t *testing.T) {
+	client, httpClient := newRetryClient(jsonResp(http.StatusOK, []WorkKind{{Id: 21}, {Id: 22}}))
+	workKinds, err := client.WorkKinds(nil)
+	require.NoError(t, err)
+	assert.Len(t, workKinds, 2)
+	assert.Equal(t, "", httpClient.requests[0].URL.RawQuery)
+}
+
+func TestPeriodsSuccess(t *testing.T) {
+	client, httpClient := newRetryClient(jsonResp(http.StatusOK, []Period{
+		{Id: 354, WeekNumber: 8, StartDate: NewDate(2021, 2, 15), EndDate: NewDate(2021, 2, 21)},
+		{Id: 355, WeekNumber: 9, StartDate: NewDate(2021, 2, 22), EndDate: NewDate(2021, 2, 28)},
+	}))
+	periods, err := client.Periods(&OptionsPD{
+		StartDate: time.Date(2021, 2, 16, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2021, 2, 22, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	require.Len(t, periods, 2)
+	assert.Equal(t, 354, periods[0].Id)
+	assert.Equal(t, 355, periods[1].Id)
+
+	query := httpClient.requests[0].URL.Query()
+	assert.Equal(t, "2021-02-16", query.Get("startDate"))
+	assert.Equal(t, "2021-02-22", query.Get("endDate"))
+}
+
+func TestPeriodsError(t *testing.T) {
+	client, _ := newRetryClient(errorResp)
+	periods, err := client.Periods(nil)
+	require.Error(t, err)
+	assert.Nil(t, periods)
+}
@@ -0,0 +1,233 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// политика повторных запросов при временных сбоях сети и сервера.
+// Незаполненные поля заменяются значениями из DefaultRetryPolicy.
+type RetryPolicy struct {
+	// максимальное число попыток, включая первую
+	MaxAttempts int
+	// задержка перед второй попыткой
+	InitialBackoff time.Duration
+	// максимальная задержка между попытками
+	MaxBackoff time.Duration
+	// во сколько раз увеличивается задержка после каждой попытки
+	Multiplier float64
+	// доля случайного отклонения задержки, от 0 до 1
+	Jitter float64
+	// HTTP-статусы ответа, при которых запрос повторяется
+	RetryableStatuses []int
+	// HTTP-методы, запросы с которыми можно повторять
+	Methods []string
+	// разрешает повторять AddLoggingTime. Перед первой попыткой клиент запоминает
+	// существующие временные затраты расписания, а перед каждым повтором проверяет,
+	// не создала ли временную затрату предыдущая попытка. Если список получить
+	// не удалось, запрос отправляется один раз без повторов
+	RetryAddLoggingTime bool
+}
+
+// DefaultRetryPolicy возвращает политику, которая повторяет идемпотентные
+// запросы до трёх раз при временных сетевых ошибках и ответах 429, 502, 503 и 504
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:       3,
+		InitialBackoff:    500 * time.Millisecond,
+		MaxBackoff:        10 * time.Second,
+		Multiplier:        2,
+		Jitter:            0.2,
+		RetryableStatuses: []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+		Methods:           []string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions},
+	}
+}
+
+// возвращается из попытки, после которой повторять запрос не нужно
+var errStopRetry = errors.New("stop retry")
+
+func (p *RetryPolicy) withDefaults() *RetryPolicy {
+	policy := *p
+	defaults := DefaultRetryPolicy()
+	if policy.MaxAttempts == 0 {
+		policy.MaxAttempts = defaults.MaxAttempts
+	}
+	if policy.InitialBackoff == 0 {
+		policy.InitialBackoff = defaults.InitialBackoff
+	}
+	if policy.MaxBackoff == 0 {
+		policy.MaxBackoff = defaults.MaxBackoff
+	}
+	if policy.Multiplier == 0 {
+		policy.Multiplier = defaults.Multiplier
+	}
+	if policy.RetryableStatuses == nil {
+		policy.RetryableStatuses = defaults.RetryableStatuses
+	}
+	if policy.Methods == nil {
+		policy.Methods = defaults.Methods
+	}
+	return &policy
+}
+
+func (p *RetryPolicy) allowsMethod(httpMethod string) bool {
+	for _, method := range p.Methods {
+		if method == httpMethod {
+			return true
+		}
+	}
+	return false
+}
+
+// повторять ли AddLoggingTime: политика разрешает это и допускает больше одной попытки
+func (p *RetryPolicy) retriesAddLoggingTime() bool {
+	return p != nil && p.RetryAddLoggingTime && p.withDefaults().MaxAttempts > 1
+}
+
+// временная сетевая ошибка: таймаут, разрыв соединения или ответ, оборванный на середине.
+// Ошибки в адресе запроса, проверки сертификата и редиректов не повторяются
+func transientError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+func (p *RetryPolicy) retryableStatus(statusCode int) bool {
+	for _, status := range p.RetryableStatuses {
+		if status == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// задержка перед попыткой с номером attempt+1
+func (p *RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	delay := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(attempt-1))
+	if delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * (2*rand.Float64() - 1)
+	}
+	if retryAfter, ok := parseRetryAfter(resp); ok && float64(retryAfter) > delay {
+		delay = float64(retryAfter)
+		if delay > float64(p.MaxBackoff) {
+			delay = float64(p.MaxBackoff)
+		}
+	}
+	return time.Duration(delay)
+}
+
+// разбирает заголовок Retry-After, заданный в секундах или датой
+func parseRetryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date), true
+	}
+	return 0, false
+}
+
+// выполняет attemptFunc, повторяя её согласно политике клиента при временных сетевых
+// ошибках и повторяемых статусах ответа.
+// Последний ответ с неуспешным статусом возвращается вызывающему без изменений.
+func (c *Client) withRetry(ctx context.Context, httpMethod string, URN string, attemptFunc func(attempt int) (*http.Response, error)) (*http.Response, error) {
+	policy := c.RetryPolicy.withDefaults()
+	for attempt := 1; ; attempt++ {
+		resp, err := attemptFunc(attempt)
+		if attempt >= policy.MaxAttempts || ctx.Err() != nil {
+			return resp, err
+		}
+		if err != nil && !transientError(err) {
+			return resp, err
+		}
+		if err == nil && !policy.retryableStatus(resp.StatusCode) {
+			return resp, nil
+		}
+		delay := policy.backoff(attempt, resp)
+		c.logRetry(httpMethod, URN, attempt, resp, err, delay)
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// создаёт временную затрату с повторами. Если предыдущая попытка успела создать
+// временную затрату, возвращает её вместо повторного запроса
+func (c *Client) addLoggingTimeWithRetry(ctx context.Context, scheduleId ScheduleId, URN string, loggingTime *AddLoggingTime, reqB []byte) (*http.Response, *LoggingTime, error) {
+	existing, err := AllLoggingTimesContext(ctx, c, scheduleId, &OptionsLT{Size: 50})
+	if err != nil {
+		// без списка существующих затрат повтор может создать дубликат
+		resp, err := c.doRequest(ctx, http.MethodPost, URN, reqB)
+		return resp, nil, err
+	}
+	known := make(map[int]bool, len(existing))
+	for _, item := range existing {
+		known[item.Id] = true
+	}
+
+	var created *LoggingTime
+	resp, err := c.withRetry(ctx, http.MethodPost, URN, func(attempt int) (*http.Response, error) {
+		if attempt > 1 {
+			loggingTimes, err := AllLoggingTimesContext(ctx, c, scheduleId, &OptionsLT{Size: 50})
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range loggingTimes {
+				if !known[item.Id] && sameLoggingTime(item, loggingTime) {
+					created = item
+					return nil, errStopRetry
+				}
+			}
+		}
+		return c.doRequest(ctx, http.MethodPost, URN, reqB)
+	})
+	if created != nil {
+		return nil, created, nil
+	}
+	return resp, nil, err
+}
+
+func sameLoggingTime(l *LoggingTime, a *AddLoggingTime) bool {
+	return l.ProjectId == a.ProjectId &&
+		l.WorkKindId == a.WorkKindId &&
+		l.Task == a.Task &&
+		l.CommentEmployee == a.CommentEmployee &&
+		l.Day1Time == a.Day1Time &&
+		l.Day2Time == a.Day2Time &&
+		l.Day3Time == a.Day3Time &&
+		l.Day4Time == a.Day4Time &&
+		l.Day5Time == a.Day5Time &&
+		l.Day6Time == a.Day6Time &&
+		l.Day7Time == a.Day7Time
+}
@@ -0,0 +1,221 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// возвращает ответы по очереди и запоминает полученные запросы
+type scriptedHttpClient struct {
+	responses []func(req *http.Request) (*http.Response, error)
+	requests  []*http.Request
+}
+
+func (m *scriptedHttpClient) Do(req *http.Request) (*http.Response, error) {
+	m.requests = append(m.requests, req)
+	if len(m.requests) > len(m.responses) {
+		return nil, errors.New("unexpected request")
+	}
+	return m.responses[len(m.requests)-1](req)
+}
+
+func statusResp(statusCode int, body string) func(req *http.Request) (*http.Response, error) {
+	return func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: statusCode,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+		}, nil
+	}
+}
+
+func jsonResp(statusCode int, v interface{}) func(req *http.Request) (*http.Response, error) {
+	respB, _ := json.Marshal(v)
+	return statusResp(statusCode, string(respB))
+}
+
+func errorResp(req *http.Request) (*http.Response, error) {
+	return nil, &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+}
+
+func newRetryClient(responses ...func(req *http.Request) (*http.Response, error)) (*Client, *scriptedHttpClient) {
+	httpClient := &scriptedHttpClient{responses: responses}
+	return &Client{
+		BaseURL:      BaseURL,
+		AccessToken:  "fake_access_token",
+		RefreshToken: "fake_refresh_token",
+		HttpClient:   httpClient,
+		RetryPolicy: &RetryPolicy{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     5 * time.Millisecond,
+		},
+	}, httpClient
+}
+
+func TestRetryGetOnServiceUnavailable(t *testing.T) {
+	client, httpClient := newRetryClient(
+		statusResp(http.StatusServiceUnavailable, "Service Unavailable"),
+		errorResp,
+		jsonResp(http.StatusOK, fakeSchedule1),
+	)
+	schedule, err := client.DetailSchedule(777)
+	require.NoError(t, err)
+	assert.Equal(t, fakeSchedule1.Period, schedule.Period)
+	assert.Len(t, httpClient.requests, 3)
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	client, httpClient := newRetryClient(
+		statusResp(http.StatusBadGateway, "Bad Gateway"),
+		statusResp(http.StatusBadGateway, "Bad Gateway"),
+		statusResp(http.StatusBadGateway, "Bad Gateway"),
+	)
+	schedule, err := client.DetailSchedule(777)
+	apiErr := &APIError{}
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+	assert.Nil(t, schedule)
+	assert.Len(t, httpClient.requests, 3)
+}
+
+func TestRetryNotRetryableStatus(t *testing.T) {
+	client, httpClient := newRetryClient(
+		statusResp(http.StatusNotFound, "Not Found"),
+	)
+	_, err := client.DetailSchedule(777)
+	require.ErrorIs(t, err, ErrNotFound)
+	assert.Len(t, httpClient.requests, 1)
+}
+
+func TestRetryPermanentError(t *testing.T) {
+	client, httpClient := newRetryClient(
+		func(req *http.Request) (*http.Response, error) {
+			return nil, &url.Error{Op: "Get", URL: req.URL.String(), Err: x509.UnknownAuthorityError{}}
+		},
+		jsonResp(http.StatusOK, fakeSchedule1),
+	)
+	_, err := client.DetailSchedule(777)
+	require.ErrorAs(t, err, &x509.UnknownAuthorityError{})
+	assert.Len(t, httpClient.requests, 1)
+}
+
+func TestRetrySkipsPostByDefault(t *testing.T) {
+	client, httpClient := newRetryClient(
+		statusResp(http.StatusServiceUnavailable, "Service Unavailable"),
+		jsonResp(http.StatusCreated, fakeSchedule1),
+	)
+	_, err := client.AddSchedule(5)
+	require.Error(t, err)
+	assert.Len(t, httpClient.requests, 1)
+}
+
+func TestRetryContextCanceledDuringBackoff(t *testing.T) {
+	client, httpClient := newRetryClient(
+		statusResp(http.StatusServiceUnavailable, "Service Unavailable"),
+		jsonResp(http.StatusOK, fakeSchedule1),
+	)
+	client.RetryPolicy.InitialBackoff = time.Hour
+	client.RetryPolicy.MaxBackoff = time.Hour
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := client.DetailScheduleContext(ctx, 777)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Len(t, httpClient.requests, 1)
+}
+
+func TestRetryAddLoggingTimeGuard(t *testing.T) {
+	addLoggingTime := &AddLoggingTime{Day1Time: 8, ProjectId: 1, Task: "task", WorkKindId: 2}
+	existing := LoggingTime{Id: 1, Day1Time: 8, ProjectId: 1, Task: "task", WorkKindId: 2}
+	created := existing
+	created.Id = 2
+	client, httpClient := newRetryClient(
+		jsonResp(http.StatusOK, []LoggingTime{existing}),
+		errorResp,
+		jsonResp(http.StatusOK, []LoggingTime{existing, created}),
+	)
+	client.RetryPolicy.RetryAddLoggingTime = true
+	loggingTime, err := client.AddLoggingTime(777, addLoggingTime)
+	require.NoError(t, err)
+	assert.Equal(t, 2, loggingTime.Id)
+	require.Len(t, httpClient.requests, 3)
+	assert.Equal(t, http.MethodPost, httpClient.requests[1].Method)
+	assert.Equal(t, http.MethodGet, httpClient.requests[2].Method)
+}
+
+func TestRetryAddLoggingTimeResend(t *testing.T) {
+	addLoggingTime := &AddLoggingTime{Day1Time: 8, ProjectId: 1, Task: "task", WorkKindId: 2}
+	created := LoggingTime{Id: 2, Day1Time: 8, ProjectId: 1, Task: "task", WorkKindId: 2}
+	client, httpClient := newRetryClient(
+		jsonResp(http.StatusOK, []LoggingTime{}),
+		statusResp(http.StatusServiceUnavailable, "Service Unavailable"),
+		jsonResp(http.StatusOK, []LoggingTime{}),
+		jsonResp(http.StatusCreated, created),
+	)
+	client.RetryPolicy.RetryAddLoggingTime = true
+	loggingTime, err := client.AddLoggingTime(777, addLoggingTime)
+	require.NoError(t, err)
+	assert.Equal(t, 2, loggingTime.Id)
+	require.Len(t, httpClient.requests, 4)
+	assert.Equal(t, http.MethodPost, httpClient.requests[3].Method)
+}
+
+func TestRetryAddLoggingTimeListFailed(t *testing.T) {
+	created := LoggingTime{Id: 2, Day1Time: 8, ProjectId: 1, Task: "task", WorkKindId: 2}
+	client, httpClient := newRetryClient(
+		statusResp(http.StatusInternalServerError, "Internal Server Error"),
+		jsonResp(http.StatusCreated, created),
+	)
+	client.RetryPolicy.RetryAddLoggingTime = true
+	loggingTime, err := client.AddLoggingTime(777, &AddLoggingTime{Day1Time: 8, ProjectId: 1, Task: "task", WorkKindId: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 2, loggingTime.Id)
+	require.Len(t, httpClient.requests, 2)
+	assert.Equal(t, http.MethodPost, httpClient.requests[1].Method)
+}
+
+func TestRetryAddLoggingTimeSingleAttempt(t *testing.T) {
+	created := LoggingTime{Id: 2, Day1Time: 8, ProjectId: 1, Task: "task", WorkKindId: 2}
+	client, httpClient := newRetryClient(jsonResp(http.StatusCreated, created))
+	client.RetryPolicy.RetryAddLoggingTime = true
+	client.RetryPolicy.MaxAttempts = 1
+	_, err := client.AddLoggingTime(777, &AddLoggingTime{Day1Time: 8, ProjectId: 1, Task: "task", WorkKindId: 2})
+	require.NoError(t, err)
+	require.Len(t, httpClient.requests, 1)
+	assert.Equal(t, http.MethodPost, httpClient.requests[0].Method)
+}
+
+func TestRetryAfter(t *testing.T) {
+	policy := (&RetryPolicy{InitialBackoff: time.Millisecond, MaxBackoff: time.Minute}).withDefaults()
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"3"}}}
+	assert.Equal(t, 3*time.Second, policy.backoff(1, resp))
+
+	policy.MaxBackoff = time.Second
+	assert.Equal(t, time.Second, policy.backoff(1, resp))
+
+	_, ok := parseRetryAfter(&http.Response{Header: http.Header{"Retry-After": []string{"soon"}}})
+	assert.False(t, ok)
+}
+
+func TestBackoffGrows(t *testing.T) {
+	policy := (&RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}).withDefaults()
+	policy.Jitter = 0
+	assert.Equal(t, 100*time.Millisecond, policy.backoff(1, nil))
+	assert.Equal(t, 200*time.Millisecond, policy.backoff(2, nil))
+	assert.Equal(t, 400*time.Millisecond, policy.backoff(3, nil))
+	assert.Equal(t, time.Second, policy.backoff(10, nil))
+}
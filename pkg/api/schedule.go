@@ -1,13 +1,6 @@
 package api
 
-type StatusCode string
-
-const (
-	Approved  StatusCode = "УТВ"
-	Declined  StatusCode = "ОТКЛ"
-	Created   StatusCode = "СЗ"
-	ToApprove StatusCode = "НУ"
-)
+import "context"
 
 type Employee struct {
 	Email      string `json:"email"`
@@ -18,26 +11,66 @@ type Employee struct {
 }
 
 type Period struct {
-	CloseDate  string `json:"closeDate"`
-	EndDate    string `json:"endDate"`
-	Id         int    `json:"id"`
-	StartDate  string `json:"startDate"`
-	WeekNumber int    `json:"weekNumber"`
+	CloseDate  Date `json:"closeDate"`
+	EndDate    Date `json:"endDate"`
+	Id         int  `json:"id"`
+	StartDate  Date `json:"startDate"`
+	WeekNumber int  `json:"weekNumber"`
 }
 
 type Schedule struct {
-	client     *Client
-	Author     Employee `json:"author"`
-	Id         int      `json:"id"`
-	Period     Period   `json:"period"`
-	StatusCode string   `json:"statusCode"`
+	client     API
+	Author     Employee   `json:"author"`
+	Id         int        `json:"id"`
+	Period     Period     `json:"period"`
+	StatusCode StatusCode `json:"statusCode"`
+}
+
+// SetClient задаёт клиент, через который методы расписания отправляют запросы.
+// Клиенты пакета api задают его сами, метод нужен другим реализациям API
+func (s *Schedule) SetClient(client API) {
+	s.client = client
+}
+
+// CanSubmit сообщает, можно ли отправить расписание на утверждение
+func (s *Schedule) CanSubmit() bool {
+	return s.StatusCode.CanSubmit()
+}
+
+// CanEdit сообщает, можно ли добавлять и изменять временные затраты расписания
+func (s *Schedule) CanEdit() bool {
+	return s.StatusCode.CanEdit()
 }
 
 func (s *Schedule) SubmitForApproveSchedule() (*Schedule, error) {
+	return s.SubmitForApproveScheduleContext(context.Background())
+}
+
+func (s *Schedule) SubmitForApproveScheduleContext(ctx context.Context) (*Schedule, error) {
+	if err := s.StatusCode.Check(ActionSubmit); err != nil {
+		return nil, err
+	}
 	scheduleId := ScheduleId(s.Id)
-	scheduleResp, err := s.client.SubmitForApproveSchedule(scheduleId)
+	scheduleResp, err := s.client.SubmitForApproveScheduleContext(ctx, scheduleId)
 	if err != nil {
 		return nil, err
 	}
 	return scheduleResp, nil
 }
+
+// AddLoggingTime добавляет временную затрату в расписание
+func (s *Schedule) AddLoggingTime(loggingTime *AddLoggingTime) (*LoggingTime, error) {
+	return s.AddLoggingTimeContext(context.Background(), loggingTime)
+}
+
+func (s *Schedule) AddLoggingTimeContext(ctx context.Context, loggingTime *AddLoggingTime) (*LoggingTime, error) {
+	if err := s.StatusCode.Check(ActionEdit); err != nil {
+		return nil, err
+	}
+	scheduleId := ScheduleId(s.Id)
+	loggingTimeResp, err := s.client.AddLoggingTimeContext(ctx, scheduleId, loggingTime)
+	if err != nil {
+		return nil, err
+	}
+	return loggingTimeResp, nil
+}
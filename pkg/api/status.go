@@ -0,0 +1,110 @@
+package api
+
+type StatusCode string
+
+const (
+	Approved  StatusCode = "УТВ"
+	Declined  StatusCode = "ОТКЛ"
+	Created   StatusCode = "СЗ"
+	ToApprove StatusCode = "НУ"
+)
+
+// действие над расписанием или временной затратой, допустимость которого зависит от статуса
+type Action string
+
+const (
+	ActionSubmit  Action = "submit"
+	ActionEdit    Action = "edit"
+	ActionApprove Action = "approve"
+	ActionDecline Action = "decline"
+)
+
+type statusInfo struct {
+	labelRu string
+	labelEn string
+	// статусы, в которые можно перейти из текущего
+	next []StatusCode
+	// можно ли изменять затраты в этом статусе
+	editable bool
+}
+
+var statuses = map[StatusCode]statusInfo{
+	Created:   {labelRu: "Создано", labelEn: "Created", next: []StatusCode{ToApprove}, editable: true},
+	ToApprove: {labelRu: "На утверждении", labelEn: "Pending approval", next: []StatusCode{Approved, Declined}},
+	Approved:  {labelRu: "Утверждено", labelEn: "Approved"},
+	Declined:  {labelRu: "Отклонено", labelEn: "Declined", next: []StatusCode{ToApprove}, editable: true},
+}
+
+// Known сообщает, известен ли статус клиенту. Для неизвестных статусов
+// проверки не выполняются, решение остаётся за сервером
+func (s StatusCode) Known() bool {
+	_, ok := statuses[s]
+	return ok
+}
+
+// LabelRu возвращает название статуса на русском языке
+func (s StatusCode) LabelRu() string {
+	if info, ok := statuses[s]; ok {
+		return info.labelRu
+	}
+	return string(s)
+}
+
+// LabelEn возвращает название статуса на английском языке
+func (s StatusCode) LabelEn() string {
+	if info, ok := statuses[s]; ok {
+		return info.labelEn
+	}
+	return string(s)
+}
+
+// CanTransition сообщает, допустим ли переход из статуса s в статус to
+func (s StatusCode) CanTransition(to StatusCode) bool {
+	info, ok := statuses[s]
+	if !ok {
+		return true
+	}
+	for _, next := range info.next {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// CanSubmit сообщает, можно ли отправить расписание на утверждение
+func (s StatusCode) CanSubmit() bool {
+	return s.CanTransition(ToApprove)
+}
+
+// CanEdit сообщает, можно ли добавлять, изменять и удалять временные затраты
+func (s StatusCode) CanEdit() bool {
+	info, ok := statuses[s]
+	return !ok || info.editable
+}
+
+// CanApprove сообщает, можно ли утвердить или отклонить временную затрату
+func (s StatusCode) CanApprove() bool {
+	return s.CanTransition(Approved) && s.CanTransition(Declined)
+}
+
+// Allows сообщает, допустимо ли действие в статусе s
+func (s StatusCode) Allows(action Action) bool {
+	switch action {
+	case ActionSubmit:
+		return s.CanSubmit()
+	case ActionEdit:
+		return s.CanEdit()
+	case ActionApprove, ActionDecline:
+		return s.CanApprove()
+	}
+	return true
+}
+
+// Check возвращает *StatusError, если действие недопустимо в статусе s
+func (s StatusCode) Check(action Action) error {
+	if s.Allows(action) {
+		return nil
+	}
+	return &StatusError{Action: action, Status: s}
+}
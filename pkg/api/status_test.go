@@ -0,0 +1,87 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusTransitions(t *testing.T) {
+	tests := []struct {
+		status     StatusCode
+		canSubmit  bool
+		canEdit    bool
+		canApprove bool
+	}{
+		{Created, true, true, false},
+		{ToApprove, false, false, true},
+		{Approved, false, false, false},
+		{Declined, true, true, false},
+		{"", true, true, true},
+		{"unknown", true, true, true},
+	}
+	for _, test := range tests {
+		t.Run(string(test.status), func(t *testing.T) {
+			assert.Equal(t, test.canSubmit, test.status.CanSubmit())
+			assert.Equal(t, test.canEdit, test.status.CanEdit())
+			assert.Equal(t, test.canApprove, test.status.CanApprove())
+		})
+	}
+}
+
+func TestStatusLabels(t *testing.T) {
+	assert.Equal(t, "На утверждении", ToApprove.LabelRu())
+	assert.Equal(t, "Pending approval", ToApprove.LabelEn())
+	assert.Equal(t, "XX", StatusCode("XX").LabelRu())
+	assert.False(t, StatusCode("XX").Known())
+}
+
+func TestStatusCheck(t *testing.T) {
+	require.NoError(t, ToApprove.Check(ActionApprove))
+	err := Created.Check(ActionApprove)
+	require.ErrorIs(t, err, ErrInvalidStatus)
+	require.ErrorIs(t, err, ErrConflict)
+	statusErr := &StatusError{}
+	require.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, ActionApprove, statusErr.Action)
+	assert.Equal(t, "approve is not allowed in status СЗ (Created)", err.Error())
+}
+
+func TestLoggingTimeRejectedBeforeRequest(t *testing.T) {
+	client, httpClient := newRetryClient()
+	loggingTime := &LoggingTime{Id: 5, scheduleId: 777, client: client, StatusCode: Created}
+
+	_, err := loggingTime.ApproveLoggingTime("ok")
+	require.ErrorIs(t, err, ErrInvalidStatus)
+	loggingTime.StatusCode = Approved
+	_, err = loggingTime.Update(&EditLoggingTime{})
+	require.ErrorIs(t, err, ErrInvalidStatus)
+	err = loggingTime.DeleteLoggingTime()
+	require.ErrorIs(t, err, ErrInvalidStatus)
+	assert.Empty(t, httpClient.requests)
+}
+
+func TestScheduleRejectedBeforeRequest(t *testing.T) {
+	client, httpClient := newRetryClient()
+	schedule := &Schedule{Id: 777, client: client, StatusCode: ToApprove}
+
+	_, err := schedule.SubmitForApproveSchedule()
+	require.ErrorIs(t, err, ErrInvalidStatus)
+	_, err = schedule.AddLoggingTime(&AddLoggingTime{Day1Time: 8})
+	require.ErrorIs(t, err, ErrInvalidStatus)
+	assert.Empty(t, httpClient.requests)
+}
+
+func TestScheduleAddLoggingTime(t *testing.T) {
+	client, httpClient := newRetryClient(jsonResp(http.StatusCreated, fakeLoggingTime1))
+	schedule := &Schedule{Id: 777, client: client, StatusCode: Created}
+
+	loggingTime, err := schedule.AddLoggingTime(&AddLoggingTime{Day1Time: 8})
+	require.NoError(t, err)
+	assert.Equal(t, fakeLoggingTime1.Id, loggingTime.Id)
+	require.Len(t, httpClient.requests, 1)
+	assert.Equal(t, http.MethodPost, httpClient.requests[0].Method)
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"context"
+	"time"
+)
+
+// норма часов за неделю по умолчанию
+const DefaultWeeklyNorm float64 = 40
+
+// часы за один день периода
+type DayHours struct {
+	// дата дня, нулевая, если у периода нет дат
+	Date    Date
+	Weekday time.Weekday
+	Hours   float64
+}
+
+// сводка по временным затратам расписания
+type ScheduleSummary struct {
+	Period Period
+	// часы по дням периода, от Day1Time до Day7Time
+	Days       []DayHours
+	ByProject  map[int]float64
+	ByWorkKind map[int]float64
+	ByStatus   map[StatusCode]float64
+	Total      float64
+	// ожидаемое количество часов за неделю
+	Norm float64
+}
+
+// Summarize считает сводку по временным затратам периода. Если norm равна 0,
+// используется DefaultWeeklyNorm
+func Summarize(period Period, loggingTimes []*LoggingTime, norm float64) *ScheduleSummary {
+	if norm == 0 {
+		norm = DefaultWeeklyNorm
+	}
+	summary := &ScheduleSummary{
+		Period:     period,
+		Days:       make([]DayHours, daysInWeek),
+		ByProject:  map[int]float64{},
+		ByWorkKind: map[int]float64{},
+		ByStatus:   map[StatusCode]float64{},
+		Norm:       norm,
+	}
+	dates := period.Dates()
+	for i := range summary.Days {
+		summary.Days[i].Weekday = time.Weekday((i + 1) % daysInWeek)
+		if i < len(dates) {
+			summary.Days[i].Date = dates[i]
+			summary.Days[i].Weekday = dates[i].Weekday()
+		}
+	}
+	for _, loggingTime := range loggingTimes {
+		for i, hours := range loggingTime.days() {
+			summary.Days[i].Hours += *hours
+		}
+		total := loggingTime.TotalHours()
+		summary.ByProject[loggingTime.ProjectId] += total
+		summary.ByWorkKind[loggingTime.WorkKindId] += total
+		summary.ByStatus[loggingTime.StatusCode] += total
+		summary.Total += total
+	}
+	return summary
+}
+
+// Complete сообщает, набрана ли норма часов за неделю
+func (s *ScheduleSummary) Complete() bool {
+	return s.Total >= s.Norm
+}
+
+// Missing возвращает количество часов, которых не хватает до нормы
+func (s *ScheduleSummary) Missing() float64 {
+	if s.Complete() {
+		return 0
+	}
+	return s.Norm - s.Total
+}
+
+// LoggingTimes возвращает временные затраты расписания со всех страниц
+func (s *Schedule) LoggingTimes() ([]*LoggingTime, error) {
+	return s.LoggingTimesContext(context.Background())
+}
+
+func (s *Schedule) LoggingTimesContext(ctx context.Context) ([]*LoggingTime, error) {
+	return AllLoggingTimesContext(ctx, s.client, ScheduleId(s.Id), nil)
+}
+
+// Refresh заново запрашивает расписание с сервера и обновляет поля s
+func (s *Schedule) Refresh() error {
+	return s.RefreshContext(context.Background())
+}
+
+func (s *Schedule) RefreshContext(ctx context.Context) error {
+	schedule, err := s.client.DetailScheduleContext(ctx, ScheduleId(s.Id))
+	if err != nil {
+		return err
+	}
+	*s = *schedule
+	return nil
+}
+
+// Summary возвращает сводку по временным затратам расписания
+func (s *Schedule) Summary(norm float64) (*ScheduleSummary, error) {
+	return s.SummaryContext(context.Background(), norm)
+}
+
+func (s *Schedule) SummaryContext(ctx context.Context, norm float64) (*ScheduleSummary, error) {
+	loggingTimes, err := s.LoggingTimesContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return Summarize(s.Period, loggingTimes, norm), nil
+}
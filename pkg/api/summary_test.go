@@ -0,0 +1,63 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var summaryPeriod = Period{Id: 354, WeekNumber: 7, StartDate: NewDate(2021, 2, 15), EndDate: NewDate(2021, 2, 21)}
+
+func TestSummarize(t *testing.T) {
+	loggingTimes := []*LoggingTime{
+		{Day1Time: 8, Day2Time: 8, Day3Time: 4, ProjectId: 1, WorkKindId: 21, StatusCode: Created},
+		{Day3Time: 4, Day4Time: 8, ProjectId: 2, WorkKindId: 21, StatusCode: Declined},
+		{Day5Time: 6, ProjectId: 1, WorkKindId: 22, StatusCode: Created},
+	}
+	summary := Summarize(summaryPeriod, loggingTimes, 0)
+	assert.Equal(t, 38.0, summary.Total)
+	assert.Equal(t, DefaultWeeklyNorm, summary.Norm)
+	assert.False(t, summary.Complete())
+	assert.Equal(t, 2.0, summary.Missing())
+
+	require.Len(t, summary.Days, 7)
+	assert.Equal(t, DayHours{Date: NewDate(2021, 2, 17), Weekday: time.Wednesday, Hours: 8}, summary.Days[2])
+	assert.Equal(t, time.Sunday, summary.Days[6].Weekday)
+	assert.Equal(t, map[int]float64{1: 26, 2: 12}, summary.ByProject)
+	assert.Equal(t, map[int]float64{21: 32, 22: 6}, summary.ByWorkKind)
+	assert.Equal(t, map[StatusCode]float64{Created: 26, Declined: 12}, summary.ByStatus)
+}
+
+func TestSummarizeWithoutDates(t *testing.T) {
+	summary := Summarize(Period{WeekNumber: 7}, []*LoggingTime{{Day1Time: 8, Day7Time: 32}}, 40)
+	assert.True(t, summary.Complete())
+	assert.Equal(t, 0.0, summary.Missing())
+	assert.True(t, summary.Days[0].Date.IsZero())
+	assert.Equal(t, time.Monday, summary.Days[0].Weekday)
+	assert.Equal(t, time.Sunday, summary.Days[6].Weekday)
+}
+
+func TestScheduleSummary(t *testing.T) {
+	client, httpClient := newRetryClient(jsonResp(http.StatusOK, []LoggingTime{
+		{Id: 1, Day1Time: 8, ProjectId: 1},
+		{Id: 2, Day2Time: 8, ProjectId: 1},
+	}))
+	schedule := &Schedule{Id: 777, Period: summaryPeriod, client: client}
+	summary, err := schedule.Summary(16)
+	require.NoError(t, err)
+	assert.True(t, summary.Complete())
+	assert.Equal(t, map[int]float64{1: 16}, summary.ByProject)
+	require.Len(t, httpClient.requests, 1)
+	assert.Contains(t, httpClient.requests[0].URL.Path, "/777/")
+}
+
+func TestScheduleRefresh(t *testing.T) {
+	client, _ := newRetryClient(jsonResp(http.StatusOK, Schedule{Id: 777, StatusCode: ToApprove}))
+	schedule := &Schedule{Id: 777, StatusCode: Created, client: client}
+	require.NoError(t, schedule.Refresh())
+	assert.Equal(t, ToApprove, schedule.StatusCode)
+	assert.Equal(t, client, schedule.client)
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"suftsdk/internal/auth"
+	"suftsdk/pkg/logging"
+)
+
+// выполняющееся обновление токенов, результат которого ждут параллельные запросы
+type refreshCall struct {
+	done        chan struct{}
+	accessToken string
+	err         error
+}
+
+// Tokens возвращает текущие токены клиента
+func (c *Client) Tokens() (accessToken string, refreshToken string) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	return c.AccessToken, c.RefreshToken
+}
+
+func (c *Client) accessToken() string {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	return c.AccessToken
+}
+
+// обновляет токены после ответа 401 на запрос с токеном staleToken и возвращает
+// новый access-токен. Если токены уже обновил другой запрос, сервер не вызывается,
+// а параллельные вызовы ждут результата одного запроса обновления.
+func (c *Client) refreshTokens(ctx context.Context, staleToken string) (string, error) {
+	for {
+		c.tokenMu.Lock()
+		if c.AccessToken != staleToken {
+			accessToken := c.AccessToken
+			c.tokenMu.Unlock()
+			return accessToken, nil
+		}
+		call := c.refreshing
+		if call == nil {
+			call = &refreshCall{done: make(chan struct{})}
+			c.refreshing = call
+			refreshToken := c.RefreshToken
+			c.tokenMu.Unlock()
+			c.doRefresh(ctx, call, refreshToken)
+			return call.accessToken, call.err
+		}
+		c.tokenMu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-call.done:
+		}
+		// запрос, который обновлял токены, был отменён своим контекстом - пробуем сами
+		if isContextError(call.err) && ctx.Err() == nil {
+			continue
+		}
+		return call.accessToken, call.err
+	}
+}
+
+func (c *Client) doRefresh(ctx context.Context, call *refreshCall, refreshToken string) {
+	tokens, err := auth.RefreshContext(ctx, refreshToken, &auth.Options{
+		SuftAPIURL: c.baseURL(),
+		HttpClient: c.httpClient(),
+		Logger:     c.Logger,
+	})
+	if err != nil {
+		c.logger().Log(logging.Warn, "unable to refresh tokens", logging.Field{Key: logging.FieldError, Value: err})
+	} else {
+		c.logger().Log(logging.Info, "tokens refreshed")
+	}
+	c.tokenMu.Lock()
+	if err == nil {
+		c.AccessToken = tokens.AccessToken
+		c.RefreshToken = tokens.RefreshToken
+		call.accessToken = tokens.AccessToken
+	}
+	call.err = err
+	c.refreshing = nil
+	c.tokenMu.Unlock()
+	close(call.done)
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
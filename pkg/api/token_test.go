@@ -0,0 +1,127 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// сервер, у которого истёк access-токен клиента: запросы со старым токеном
+// получают 401, обновление токенов выдаёт новый токен
+type expiredTokenHttpClient struct {
+	refreshes int32
+	mu        sync.Mutex
+	bodies    []string
+}
+
+func (m *expiredTokenHttpClient) Do(req *http.Request) (*http.Response, error) {
+	if strings.HasSuffix(req.URL.Path, "/security/refresh-token") {
+		atomic.AddInt32(&m.refreshes, 1)
+		time.Sleep(10 * time.Millisecond)
+		header := http.Header{}
+		header.Add("Set-Cookie", "Access-token=new_access_token")
+		header.Add("Set-Cookie", "Refresh-token=new_refresh_token")
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     header,
+			Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+		}, nil
+	}
+	cookie, err := req.Cookie("Access-token")
+	if err != nil || cookie.Value != "new_access_token" {
+		return &http.Response{
+			StatusCode: http.StatusUnauthorized,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte("Unauthorized"))),
+		}, nil
+	}
+	reqB, _ := ioutil.ReadAll(req.Body)
+	m.mu.Lock()
+	m.bodies = append(m.bodies, string(reqB))
+	m.mu.Unlock()
+
+	statusCode := http.StatusOK
+	if req.Method == http.MethodPost {
+		statusCode = http.StatusCreated
+	}
+	respB, _ := json.Marshal(fakeLoggingTime1)
+	return &http.Response{
+		StatusCode: statusCode,
+		Body:       ioutil.NopCloser(bytes.NewReader(respB)),
+	}, nil
+}
+
+func newExpiredTokenClient() (*Client, *expiredTokenHttpClient) {
+	httpClient := &expiredTokenHttpClient{}
+	return &Client{
+		BaseURL:      BaseURL,
+		AccessToken:  "fake_access_token",
+		RefreshToken: "fake_refresh_token",
+		HttpClient:   httpClient,
+	}, httpClient
+}
+
+func TestConcurrentRefresh(t *testing.T) {
+	client, httpClient := newExpiredTokenClient()
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.DetailLoggingTime(777, 777)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&httpClient.refreshes))
+	accessToken, refreshToken := client.Tokens()
+	assert.Equal(t, "new_access_token", accessToken)
+	assert.Equal(t, "new_refresh_token", refreshToken)
+}
+
+func TestRefreshReplaysBody(t *testing.T) {
+	client, httpClient := newExpiredTokenClient()
+	addLoggingTime := &AddLoggingTime{Day1Time: 8, ProjectId: 1, Task: "task", WorkKindId: 2}
+	_, err := client.AddLoggingTime(777, addLoggingTime)
+	require.NoError(t, err)
+	expected, _ := json.Marshal(addLoggingTime)
+	require.Len(t, httpClient.bodies, 1)
+	assert.Equal(t, string(expected), httpClient.bodies[0])
+}
+
+func TestRefreshSkippedWhenTokenAlreadyUpdated(t *testing.T) {
+	client, httpClient := newExpiredTokenClient()
+	client.AccessToken = "new_access_token"
+	accessToken, err := client.refreshTokens(context.Background(), "fake_access_token")
+	require.NoError(t, err)
+	assert.Equal(t, "new_access_token", accessToken)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&httpClient.refreshes))
+}
+
+func TestNewClientUnauthorized(t *testing.T) {
+	httpClient := &scriptedHttpClient{responses: []func(*http.Request) (*http.Response, error){
+		jsonResp(http.StatusUnauthorized, ErrorBody{Status: 401, Error: "Unauthorized", Message: "Bad credentials"}),
+	}}
+	client, err := NewClient("demo@example.com", "wrong", &OptionsNC{HttpClient: httpClient})
+	require.ErrorIs(t, err, ErrUnauthorized)
+	assert.Nil(t, client)
+	apiErr := &APIError{}
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+	assert.Equal(t, http.MethodPost, apiErr.Method)
+	assert.Equal(t, "Bad credentials", apiErr.Message())
+}
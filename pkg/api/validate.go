@@ -0,0 +1,168 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ErrValidation возвращается, если временная затрата не прошла проверку на клиенте
+var ErrValidation = errors.New("validation failed")
+
+// правила проверки временной затраты перед отправкой
+type ValidationRules struct {
+	// шаг часов, например 0.5 - только целые и половины часа; 0 - не проверяется
+	HourStep float64
+	// максимум часов за день; 0 - не проверяется
+	MaxDayHours float64
+	// максимум часов за неделю; 0 - не проверяется
+	MaxWeekHours float64
+	// за неделю должен быть указан хотя бы один час
+	RequireHours bool
+	// обязательные поля
+	RequireTask     bool
+	RequireProject  bool
+	RequireWorkKind bool
+}
+
+// DefaultValidationRules возвращает правила, используемые методом Validate
+func DefaultValidationRules() ValidationRules {
+	return ValidationRules{
+		HourStep:        0.25,
+		MaxDayHours:     24,
+		RequireHours:    true,
+		RequireTask:     true,
+		RequireProject:  true,
+		RequireWorkKind: true,
+	}
+}
+
+// код ошибки проверки, по которому приложение может показать сообщение на своём языке
+type ValidationCode string
+
+const (
+	// часы отрицательные
+	CodeNegativeHours ValidationCode = "negative_hours"
+	// часов за день больше MaxDayHours, Limit - максимум
+	CodeMaxDayHours ValidationCode = "max_day_hours"
+	// часы не кратны HourStep, Limit - шаг
+	CodeHourStep ValidationCode = "hour_step"
+	// часов за неделю больше MaxWeekHours, Value - сумма за неделю, Limit - максимум
+	CodeMaxWeekHours ValidationCode = "max_week_hours"
+	// не указано ни одного часа за неделю
+	CodeNoHours ValidationCode = "no_hours"
+	// поле не заполнено
+	CodeRequired ValidationCode = "required"
+)
+
+// ошибка в значении поля, Field - имя поля в JSON
+type FieldError struct {
+	Field string
+	Code  ValidationCode
+	// значение правила: максимум часов или шаг
+	Limit float64
+	// проверенное значение, заполняется для CodeMaxWeekHours
+	Value float64
+}
+
+// Message возвращает описание ошибки на английском
+func (e FieldError) Message() string {
+	switch e.Code {
+	case CodeNegativeHours:
+		return "must not be negative"
+	case CodeMaxDayHours:
+		return fmt.Sprintf("must not exceed %g hours", e.Limit)
+	case CodeHourStep:
+		return fmt.Sprintf("must be a multiple of %g", e.Limit)
+	case CodeMaxWeekHours:
+		return fmt.Sprintf("weekly total %g exceeds %g hours", e.Value, e.Limit)
+	case CodeNoHours:
+		return "at least one day must have hours"
+	case CodeRequired:
+		return "is required"
+	}
+	return string(e.Code)
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message())
+}
+
+// ошибка проверки со списком ошибок по полям, сравнивается с ErrValidation
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, fieldErr := range e.Errors {
+		messages = append(messages, fieldErr.Error())
+	}
+	return fmt.Sprintf("%s: %s", ErrValidation, strings.Join(messages, "; "))
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// Validate проверяет временную затрату по правилам DefaultValidationRules
+func (l *AddLoggingTime) Validate() error {
+	return l.ValidateWith(DefaultValidationRules())
+}
+
+// ValidateWith проверяет временную затрату по переданным правилам
+func (l *AddLoggingTime) ValidateWith(rules ValidationRules) error {
+	return rules.validate(l.days(), l.ProjectId, l.Task, l.WorkKindId)
+}
+
+// Validate проверяет изменения по правилам DefaultValidationRules
+func (l *EditLoggingTime) Validate() error {
+	return l.ValidateWith(DefaultValidationRules())
+}
+
+// ValidateWith проверяет изменения по переданным правилам
+func (l *EditLoggingTime) ValidateWith(rules ValidationRules) error {
+	return rules.validate(l.days(), l.ProjectId, l.Task, l.WorkKindId)
+}
+
+func (r ValidationRules) validate(days [daysInWeek]*float64, projectId int, task string, workKindId int) error {
+	fieldErrs := []FieldError{}
+	total := 0.0
+	for i, hours := range days {
+		field := fmt.Sprintf("day%dTime", i+1)
+		switch {
+		case *hours < 0:
+			fieldErrs = append(fieldErrs, FieldError{Field: field, Code: CodeNegativeHours})
+		case r.MaxDayHours > 0 && *hours > r.MaxDayHours:
+			fieldErrs = append(fieldErrs, FieldError{Field: field, Code: CodeMaxDayHours, Limit: r.MaxDayHours})
+		case r.HourStep > 0 && !isMultiple(*hours, r.HourStep):
+			fieldErrs = append(fieldErrs, FieldError{Field: field, Code: CodeHourStep, Limit: r.HourStep})
+		}
+		total += *hours
+	}
+	if r.MaxWeekHours > 0 && total > r.MaxWeekHours {
+		fieldErrs = append(fieldErrs, FieldError{Field: "dayTime", Code: CodeMaxWeekHours, Limit: r.MaxWeekHours, Value: total})
+	}
+	if r.RequireHours && total <= 0 {
+		fieldErrs = append(fieldErrs, FieldError{Field: "dayTime", Code: CodeNoHours})
+	}
+	if r.RequireProject && projectId <= 0 {
+		fieldErrs = append(fieldErrs, FieldError{Field: "projectId", Code: CodeRequired})
+	}
+	if r.RequireTask && strings.TrimSpace(task) == "" {
+		fieldErrs = append(fieldErrs, FieldError{Field: "task", Code: CodeRequired})
+	}
+	if r.RequireWorkKind && workKindId <= 0 {
+		fieldErrs = append(fieldErrs, FieldError{Field: "workKindId", Code: CodeRequired})
+	}
+	if len(fieldErrs) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: fieldErrs}
+}
+
+func isMultiple(value float64, step float64) bool {
+	ratio := value / step
+	return math.Abs(ratio-math.Round(ratio)) < 1e-9
+}
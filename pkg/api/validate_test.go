@@ -0,0 +1,53 @@
+package api
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddLoggingTimeValidate(t *testing.T) {
+	loggingTime := &AddLoggingTime{Day1Time: 8, Day2Time: 7.5, ProjectId: 1, Task: "task", WorkKindId: 2}
+	require.NoError(t, loggingTime.Validate())
+}
+
+func TestAddLoggingTimeValidateErrors(t *testing.T) {
+	loggingTime := &AddLoggingTime{Day1Time: -1, Day2Time: 30, Day3Time: 1.1, Task: " "}
+	err := loggingTime.Validate()
+	require.ErrorIs(t, err, ErrValidation)
+	validationErr := &ValidationError{}
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []FieldError{
+		{Field: "day1Time", Code: CodeNegativeHours},
+		{Field: "day2Time", Code: CodeMaxDayHours, Limit: 24},
+		{Field: "day3Time", Code: CodeHourStep, Limit: 0.25},
+		{Field: "projectId", Code: CodeRequired},
+		{Field: "task", Code: CodeRequired},
+		{Field: "workKindId", Code: CodeRequired},
+	}, validationErr.Errors)
+}
+
+func TestValidateWithRules(t *testing.T) {
+	loggingTime := &AddLoggingTime{Day1Time: 10, Day2Time: 10, Day3Time: 0.3}
+	rules := ValidationRules{MaxDayHours: 8, MaxWeekHours: 20}
+	err := loggingTime.ValidateWith(rules)
+	validationErr := &ValidationError{}
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []FieldError{
+		{Field: "day1Time", Code: CodeMaxDayHours, Limit: 8},
+		{Field: "day2Time", Code: CodeMaxDayHours, Limit: 8},
+		{Field: "dayTime", Code: CodeMaxWeekHours, Limit: 20, Value: 20.3},
+	}, validationErr.Errors)
+	assert.Equal(t, "validation failed: day1Time: must not exceed 8 hours; day2Time: must not exceed 8 hours; dayTime: weekly total 20.3 exceeds 20 hours", err.Error())
+
+	require.NoError(t, loggingTime.ValidateWith(ValidationRules{}))
+}
+
+func TestEditLoggingTimeValidate(t *testing.T) {
+	loggingTime := &EditLoggingTime{ProjectId: 1, Task: "task", WorkKindId: 2}
+	err := loggingTime.Validate()
+	require.ErrorIs(t, err, ErrValidation)
+	assert.Equal(t, "validation failed: dayTime: at least one day must have hours", err.Error())
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"time"
+)
+
+// PeriodForDate возвращает период, в который попадает дата
+func PeriodForDate(client API, date time.Time) (*Period, error) {
+	return PeriodForDateContext(context.Background(), client, date)
+}
+
+// Если сервер не отфильтровал периоды по дате, подходящий ищется на всех страницах
+func PeriodForDateContext(ctx context.Context, client API, date time.Time) (*Period, error) {
+	options := OptionsPD{StartDate: date, EndDate: date, Size: defaultPageSize}
+	for {
+		periods, err := client.PeriodsContext(ctx, &options)
+		if err != nil {
+			return nil, err
+		}
+		for _, period := range periods {
+			if period.Contains(date) {
+				return period, nil
+			}
+		}
+		if len(periods) < options.Size {
+			break
+		}
+		options.Page++
+	}
+	return nil, &PeriodNotFoundError{Date: date}
+}
+
+// ScheduleForDate возвращает расписание пользователя на неделю, в которую попадает дата
+func ScheduleForDate(client API, date time.Time) (*Schedule, error) {
+	return ScheduleForDateContext(context.Background(), client, date)
+}
+
+func ScheduleForDateContext(ctx context.Context, client API, date time.Time) (*Schedule, error) {
+	period, err := PeriodForDateContext(ctx, client, date)
+	if err != nil {
+		return nil, err
+	}
+	return ScheduleForPeriodContext(ctx, client, PeriodId(period.Id))
+}
+
+// EnsureScheduleForDate возвращает расписание пользователя на неделю, в которую
+// попадает дата, и создаёт его, если расписания ещё нет
+func EnsureScheduleForDate(client API, date time.Time) (*Schedule, error) {
+	return EnsureScheduleForDateContext(context.Background(), client, date)
+}
+
+func EnsureScheduleForDateContext(ctx context.Context, client API, date time.Time) (*Schedule, error) {
+	period, err := PeriodForDateContext(ctx, client, date)
+	if err != nil {
+		return nil, err
+	}
+	return EnsureScheduleForPeriodContext(ctx, client, PeriodId(period.Id))
+}
+
+// ScheduleForPeriod возвращает расписание пользователя на период
+func ScheduleForPeriod(client API, periodId PeriodId) (*Schedule, error) {
+	return ScheduleForPeriodContext(context.Background(), client, periodId)
+}
+
+func ScheduleForPeriodContext(ctx context.Context, client API, periodId PeriodId) (*Schedule, error) {
+	schedule, err := findScheduleForPeriod(ctx, client, periodId)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, &ScheduleNotFoundError{PeriodId: periodId}
+	}
+	return schedule, nil
+}
+
+// EnsureScheduleForPeriod возвращает расписание пользователя на период
+// и создаёт его, если расписания ещё нет
+func EnsureScheduleForPeriod(client API, periodId PeriodId) (*Schedule, error) {
+	return EnsureScheduleForPeriodContext(context.Background(), client, periodId)
+}
+
+func EnsureScheduleForPeriodContext(ctx context.Context, client API, periodId PeriodId) (*Schedule, error) {
+	schedule, err := findScheduleForPeriod(ctx, client, periodId)
+	if err != nil {
+		return nil, err
+	}
+	if schedule != nil {
+		return schedule, nil
+	}
+	return client.AddScheduleContext(ctx, periodId)
+}
+
+// ищет среди расписаний пользователя расписание на период, nil - расписания нет
+func findScheduleForPeriod(ctx context.Context, client API, periodId PeriodId) (*Schedule, error) {
+	it := NewScheduleIteratorContext(ctx, client, &OptionsS{Size: 20, CreatorApprover: Creator})
+	for it.Next() {
+		if PeriodId(it.Schedule().Period.Id) == periodId {
+			return it.Schedule(), nil
+		}
+	}
+	return nil, it.Err()
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var weekPeriods = []Period{
+	{Id: 354, WeekNumber: 8, StartDate: NewDate(2021, 2, 15), EndDate: NewDate(2021, 2, 21)},
+	{Id: 355, WeekNumber: 9, StartDate: NewDate(2021, 2, 22), EndDate: NewDate(2021, 2, 28)},
+}
+
+var weekDate = time.Date(2021, 2, 17, 12, 0, 0, 0, time.UTC)
+
+func TestPeriodForDate(t *testing.T) {
+	client, _ := newRetryClient(jsonResp(http.StatusOK, weekPeriods))
+	period, err := PeriodForDate(client, weekDate)
+	require.NoError(t, err)
+	assert.Equal(t, 354, period.Id)
+}
+
+func TestPeriodForDateLaterPage(t *testing.T) {
+	// сервер не фильтрует по дате: первая страница заполнена другими периодами
+	firstPage := []Period{}
+	for i := 0; i < defaultPageSize; i++ {
+		firstPage = append(firstPage, Period{Id: 340 + i, StartDate: NewDate(2020, 11, 2+7*i), EndDate: NewDate(2020, 11, 8+7*i)})
+	}
+	client, httpClient := newRetryClient(jsonResp(http.StatusOK, firstPage), jsonResp(http.StatusOK, weekPeriods))
+	period, err := PeriodForDate(client, weekDate)
+	require.NoError(t, err)
+	assert.Equal(t, 354, period.Id)
+	require.Len(t, httpClient.requests, 2)
+	assert.Equal(t, "1", httpClient.requests[1].URL.Query().Get("page"))
+}
+
+func TestPeriodForDateNotFound(t *testing.T) {
+	client, _ := newRetryClient(jsonResp(http.StatusOK, []Period{}))
+	period, err := PeriodForDate(client, weekDate)
+	require.ErrorIs(t, err, ErrNotFound)
+	assert.Nil(t, period)
+	periodErr := &PeriodNotFoundError{}
+	require.ErrorAs(t, err, &periodErr)
+	assert.Equal(t, weekDate, periodErr.Date)
+}
+
+func TestPeriodContains(t *testing.T) {
+	period := Period{WeekNumber: 7}
+	assert.True(t, period.Contains(weekDate))
+	period = Period{WeekNumber: 8}
+	assert.False(t, period.Contains(weekDate))
+	period = Period{StartDate: NewDate(2021, 2, 17), EndDate: NewDate(2021, 2, 17)}
+	assert.True(t, period.Contains(weekDate))
+	period = Period{StartDate: NewDate(2021, 2, 18), EndDate: NewDate(2021, 2, 24)}
+	assert.False(t, period.Contains(weekDate))
+}
+
+func TestScheduleForDate(t *testing.T) {
+	schedule := Schedule{Id: 32884, Period: weekPeriods[0]}
+	client, httpClient := newRetryClient(
+		jsonResp(http.StatusOK, weekPeriods),
+		jsonResp(http.StatusOK, []Schedule{{Id: 1, Period: weekPeriods[1]}, schedule}),
+	)
+	scheduleResp, err := ScheduleForDate(client, weekDate)
+	require.NoError(t, err)
+	assert.Equal(t, 32884, scheduleResp.Id)
+	assert.Equal(t, "creator", httpClient.requests[1].URL.Query().Get("creatorApprover"))
+}
+
+func TestScheduleForDateNotFound(t *testing.T) {
+	client, httpClient := newRetryClient(
+		jsonResp(http.StatusOK, weekPeriods),
+		jsonResp(http.StatusOK, []Schedule{{Id: 1, Period: weekPeriods[1]}}),
+	)
+	scheduleResp, err := ScheduleForDate(client, weekDate)
+	require.ErrorIs(t, err, ErrNotFound)
+	assert.Nil(t, scheduleResp)
+	assert.Len(t, httpClient.requests, 2)
+}
+
+func TestEnsureScheduleForDateCreates(t *testing.T) {
+	created := Schedule{Id: 40000, Period: weekPeriods[0], StatusCode: "СЗ"}
+	client, httpClient := newRetryClient(
+		jsonResp(http.StatusOK, weekPeriods),
+		jsonResp(http.StatusOK, []Schedule{}),
+		jsonResp(http.StatusCreated, created),
+	)
+	scheduleResp, err := EnsureScheduleForDate(client, weekDate)
+	require.NoError(t, err)
+	assert.Equal(t, 40000, scheduleResp.Id)
+	require.Len(t, httpClient.requests, 3)
+	assert.Equal(t, http.MethodPost, httpClient.requests[2].Method)
+}
+
+func TestEnsureScheduleForDateExisting(t *testing.T) {
+	client, httpClient := newRetryClient(
+		jsonResp(http.StatusOK, weekPeriods),
+		jsonResp(http.StatusOK, []Schedule{{Id: 32884, Period: weekPeriods[0]}}),
+	)
+	scheduleResp, err := EnsureScheduleForDate(client, weekDate)
+	require.NoError(t, err)
+	assert.Equal(t, 32884, scheduleResp.Id)
+	assert.Len(t, httpClient.requests, 2)
+}
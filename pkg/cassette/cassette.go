@@ -0,0 +1,248 @@
+// Package cassette - запись и воспроизведение HTTP-взаимодействий с СУФТ.
+// Recorder реализует api.HttpClient: в режиме Record он отправляет запросы
+// через настоящий HTTP-клиент и запоминает ответы, а Save сохраняет их в файл,
+// скрыв токены и персональные данные. В режиме Replay ответы берутся из файла
+// без обращения к сети, поэтому по кассете из отчёта об ошибке можно
+// воспроизвести поведение SDK в тесте:
+//
+//	recorder, err := cassette.New("testdata/bug.json", nil)
+//	client, err := api.NewClient("user@example.com", "secret", &api.OptionsNC{HttpClient: recorder})
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// ошибка Replay, когда в кассете нет неиспользованного взаимодействия для запроса
+var ErrNoInteraction = errors.New("cassette: no recorded interaction for request")
+
+type Mode int
+
+const (
+	// ответы берутся из файла кассеты, запросы в сеть не отправляются
+	Replay Mode = iota
+	// запросы отправляются через Options.HttpClient, взаимодействия сохраняет Save
+	Record
+)
+
+type HttpClient interface {
+	Do(r *http.Request) (*http.Response, error)
+}
+
+// Cassette - содержимое файла кассеты
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+type Request struct {
+	Method string `json:"method"`
+	// путь и параметры запроса без схемы и хоста, например /tools/suft/api/v1/api/v1/schedules?page=0
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+type Response struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+type Options struct {
+	Mode Mode
+	// клиент для запросов в режиме Record, по умолчанию http.DefaultClient
+	HttpClient HttpClient
+	// правила сопоставления запроса с записью в режиме Replay, по умолчанию DefaultMatchers
+	Matchers []Matcher
+	// поля JSON, значения которых скрываются, по умолчанию DefaultRedactFields
+	RedactFields []string
+}
+
+// Recorder можно использовать из нескольких горутин одновременно
+type Recorder struct {
+	path       string
+	mode       Mode
+	httpClient HttpClient
+	matchers   []Matcher
+	redactor   redactor
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// New создаёт Recorder для файла path. В режиме Replay файл читается сразу
+// и должен существовать, в режиме Record он записывается методом Save
+func New(path string, options *Options) (*Recorder, error) {
+	r := &Recorder{
+		path:       path,
+		httpClient: http.DefaultClient,
+		matchers:   DefaultMatchers,
+		redactor:   newRedactor(DefaultRedactFields),
+	}
+	if options != nil {
+		r.mode = options.Mode
+		if options.HttpClient != nil {
+			r.httpClient = options.HttpClient
+		}
+		if options.Matchers != nil {
+			r.matchers = options.Matchers
+		}
+		if options.RedactFields != nil {
+			r.redactor = newRedactor(options.RedactFields)
+		}
+	}
+	if r.mode == Replay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal(data, &r.cassette)
+		if err != nil {
+			return nil, fmt.Errorf("cassette %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+	return r, nil
+}
+
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if r.mode == Record {
+		return r.record(req, body)
+	}
+	return r.replay(req, body)
+}
+
+// Save записывает кассету в файл. В режиме Replay ничего не делает
+func (r *Recorder) Save() error {
+	if r.mode != Record {
+		return nil
+	}
+	r.mu.Lock()
+	data, err := json.MarshalIndent(&r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0644)
+}
+
+// Interactions возвращает записанные или загруженные из файла взаимодействия
+func (r *Recorder) Interactions() []*Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	interactions := make([]*Interaction, len(r.cassette.Interactions))
+	copy(interactions, r.cassette.Interactions)
+	return interactions
+}
+
+// Remaining возвращает количество ещё не воспроизведённых взаимодействий
+func (r *Recorder) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	remaining := 0
+	for _, used := range r.used {
+		if !used {
+			remaining++
+		}
+	}
+	return remaining
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := &Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    req.URL.RequestURI(),
+			Header: r.redactor.header(req.Header),
+			Body:   string(r.redactor.body(body)),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     r.redactor.header(resp.Header),
+			Body:       string(r.redactor.body(respBody)),
+		},
+	}
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// первое неиспользованное взаимодействие, подходящее по всем правилам
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	body = r.redactor.body(body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || !r.matches(req, body, &interaction.Request) {
+			continue
+		}
+		r.used[i] = true
+		return &http.Response{
+			Status:     fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode: interaction.Response.StatusCode,
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     interaction.Response.Header.Clone(),
+			Body:       io.NopCloser(bytes.NewReader([]byte(interaction.Response.Body))),
+			Request:    req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, req.Method, req.URL.RequestURI())
+}
+
+func (r *Recorder) matches(req *http.Request, body []byte, recorded *Request) bool {
+	for _, match := range r.matchers {
+		if !match(req, body, recorded) {
+			return false
+		}
+	}
+	return true
+}
+
+// читает тело запроса и подставляет копию, чтобы запрос можно было отправить дальше
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
@@ -0,0 +1,166 @@
+package cassette
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"suftsdk/pkg/api"
+	"suftsdk/pkg/sufttest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const replayURL = "http://suft.invalid/tools/suft/api/v1/"
+
+// записывает кассету с входом, созданием расписания и временной затраты
+func recordCassette(t *testing.T, path string) (api.PeriodId, *api.LoggingTime) {
+	server := sufttest.NewServer(nil)
+	defer server.Close()
+	server.AddUser("user@example.com", "secret", 0)
+	periods := server.Fake().AddWeeks(time.Date(2021, 2, 15, 0, 0, 0, 0, time.UTC), 1)
+
+	recorder, err := New(path, &Options{Mode: Record})
+	require.NoError(t, err)
+	loggingTime, err := runWorkflow(server.URL(), recorder, api.PeriodId(periods[0].Id))
+	require.NoError(t, err)
+	require.NoError(t, recorder.Save())
+	return api.PeriodId(periods[0].Id), loggingTime
+}
+
+func runWorkflow(baseURL string, recorder *Recorder, periodId api.PeriodId) (*api.LoggingTime, error) {
+	client, err := api.NewClient("user@example.com", "secret", &api.OptionsNC{SuftAPIURL: baseURL, HttpClient: recorder})
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := client.AddSchedule(periodId)
+	if err != nil {
+		return nil, err
+	}
+	loggingTime, err := client.AddLoggingTime(api.ScheduleId(schedule.Id), &api.AddLoggingTime{Day1Time: 8, Task: "SUFT-1"})
+	if err != nil {
+		return nil, err
+	}
+	_, err = client.DetailSchedule(12345)
+	if !errors.Is(err, api.ErrNotFound) {
+		return nil, fmt.Errorf("DetailSchedule: %v", err)
+	}
+	return loggingTime, nil
+}
+
+func TestRecordRedacts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	recordCassette(t, path)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "user@example.com")
+	assert.NotContains(t, string(data), "secret")
+
+	recorder, err := New(path, nil)
+	require.NoError(t, err)
+	interactions := recorder.Interactions()
+	require.Len(t, interactions, 4)
+	assert.Equal(t, http.MethodPost, interactions[0].Request.Method)
+	assert.Equal(t, "/tools/suft/api/v1/security/authenticate", interactions[0].Request.URL)
+	assert.Equal(t, `{"password":"REDACTED","username":"REDACTED"}`, interactions[0].Request.Body)
+	for _, cookie := range interactions[0].Response.Header.Values("Set-Cookie") {
+		assert.Regexp(t, `^(Access|Refresh)-token=REDACTED;`, cookie)
+	}
+	assert.Equal(t, "Access-token=REDACTED", interactions[1].Request.Header.Get("Cookie"))
+	assert.Contains(t, interactions[1].Response.Body, `"email":"REDACTED"`)
+	assert.Equal(t, http.StatusNotFound, interactions[3].Response.StatusCode)
+}
+
+func TestReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	periodId, recorded := recordCassette(t, path)
+
+	// сервер остановлен, ответы берутся из кассеты
+	recorder, err := New(path, nil)
+	require.NoError(t, err)
+	replayed, err := runWorkflow(replayURL, recorder, periodId)
+	require.NoError(t, err)
+	assert.Equal(t, recorded.Id, replayed.Id)
+	assert.Equal(t, recorded.Task, replayed.Task)
+	assert.Equal(t, recorded.Day1Time, replayed.Day1Time)
+	assert.Equal(t, 0, recorder.Remaining())
+
+	// каждое взаимодействие воспроизводится один раз
+	_, err = runWorkflow(replayURL, recorder, periodId)
+	assert.ErrorIs(t, err, ErrNoInteraction)
+
+	// тело запроса не совпадает с записанным
+	recorder, err = New(path, nil)
+	require.NoError(t, err)
+	_, err = runWorkflow(replayURL, recorder, periodId+1)
+	assert.ErrorIs(t, err, ErrNoInteraction)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = api.NewClientContext(ctx, "user@example.com", "secret", &api.OptionsNC{SuftAPIURL: replayURL, HttpClient: recorder})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestMatchers(t *testing.T) {
+	recorded := &Request{
+		Method: http.MethodGet,
+		URL:    "/tools/suft/api/v1/api/v1/schedules?page=0&size=5",
+		Body:   `{"a":1,"b":[1,2]}`,
+	}
+	req, err := http.NewRequest(http.MethodGet, "http://other.host/tools/suft/api/v1/api/v1/schedules?size=5&page=0", nil)
+	require.NoError(t, err)
+	assert.True(t, MatchMethod(req, nil, recorded))
+	assert.True(t, MatchURN(req, nil, recorded))
+	assert.True(t, MatchQuery(req, nil, recorded))
+	assert.True(t, MatchBody(req, []byte(`{ "b": [1, 2], "a": 1 }`), recorded))
+	assert.False(t, MatchBody(req, []byte(`{"a":2,"b":[1,2]}`), recorded))
+
+	req, err = http.NewRequest(http.MethodPost, "http://other.host/tools/suft/api/v1/api/v1/schedules/1?page=1", bytes.NewReader(nil))
+	require.NoError(t, err)
+	assert.False(t, MatchMethod(req, nil, recorded))
+	assert.False(t, MatchURN(req, nil, recorded))
+	assert.False(t, MatchQuery(req, nil, recorded))
+}
+
+func TestCustomMatchersAndRedactFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	server := sufttest.NewServer(nil)
+	defer server.Close()
+	server.AddUser("user@example.com", "secret", 0)
+	server.Fake().AddProject(api.Project{Code: "SUFT", Name: "Система учёта"})
+
+	recorder, err := New(path, &Options{Mode: Record, RedactFields: []string{"password", "name"}})
+	require.NoError(t, err)
+	client, err := api.NewClient("user@example.com", "secret", &api.OptionsNC{SuftAPIURL: server.URL(), HttpClient: recorder})
+	require.NoError(t, err)
+	_, err = client.Projects(nil)
+	require.NoError(t, err)
+	require.NoError(t, recorder.Save())
+	interactions := recorder.Interactions()
+	require.Len(t, interactions, 2)
+	assert.Equal(t, `{"password":"REDACTED","username":"user@example.com"}`, interactions[0].Request.Body)
+	assert.Contains(t, interactions[1].Response.Body, `"name":"REDACTED"`)
+
+	// запросы с другими параметрами подходят, если сравнивать только метод и URN
+	recorder, err = New(path, &Options{Matchers: []Matcher{MatchMethod, MatchURN}})
+	require.NoError(t, err)
+	client, err = api.NewClient("other@example.com", "other", &api.OptionsNC{SuftAPIURL: server.URL(), HttpClient: recorder})
+	require.NoError(t, err)
+	projects, err := client.Projects(&api.OptionsP{Page: 3})
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	assert.Equal(t, "SUFT", projects[0].Code)
+}
+
+func TestNewReplayMissingFile(t *testing.T) {
+	_, err := New(filepath.Join(t.TempDir(), "missing.json"), nil)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,51 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"reflect"
+)
+
+// Matcher сообщает, соответствует ли запрос записанному. body - тело запроса,
+// в котором уже скрыты те же данные, что и при записи
+type Matcher func(r *http.Request, body []byte, recorded *Request) bool
+
+// правила сопоставления по умолчанию: метод, URN, параметры и тело запроса
+var DefaultMatchers = []Matcher{MatchMethod, MatchURN, MatchQuery, MatchBody}
+
+func MatchMethod(r *http.Request, body []byte, recorded *Request) bool {
+	return r.Method == recorded.Method
+}
+
+// MatchURN сравнивает путь запроса без параметров, хост не учитывается
+func MatchURN(r *http.Request, body []byte, recorded *Request) bool {
+	u, err := url.Parse(recorded.URL)
+	return err == nil && r.URL.Path == u.Path
+}
+
+// MatchQuery сравнивает параметры запроса без учёта их порядка
+func MatchQuery(r *http.Request, body []byte, recorded *Request) bool {
+	u, err := url.Parse(recorded.URL)
+	if err != nil {
+		return false
+	}
+	query := r.URL.Query()
+	recordedQuery := u.Query()
+	if len(query) == 0 && len(recordedQuery) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(query, recordedQuery)
+}
+
+// MatchBody сравнивает тела запросов. JSON сравнивается по значению,
+// поэтому порядок полей и пробелы не важны
+func MatchBody(r *http.Request, body []byte, recorded *Request) bool {
+	recordedBody := []byte(recorded.Body)
+	var v, recordedV interface{}
+	if json.Unmarshal(body, &v) == nil && json.Unmarshal(recordedBody, &recordedV) == nil {
+		return reflect.DeepEqual(v, recordedV)
+	}
+	return bytes.Equal(body, recordedBody)
+}
@@ -0,0 +1,109 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// значение, которым заменяются скрытые данные
+const Redacted = "REDACTED"
+
+// поля JSON с учётными и персональными данными, регистр не учитывается
+var DefaultRedactFields = []string{"username", "password", "email", "firstName", "lastName", "middleName"}
+
+// заголовки с токенами. В Cookie и Set-Cookie скрываются только значения,
+// имена cookie сохраняются
+var redactHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+type redactor struct {
+	fields map[string]bool
+}
+
+func newRedactor(fields []string) redactor {
+	r := redactor{fields: map[string]bool{}}
+	for _, field := range fields {
+		r.fields[strings.ToLower(field)] = true
+	}
+	return r
+}
+
+func (r redactor) header(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+	header = header.Clone()
+	for _, name := range redactHeaders {
+		values := header.Values(name)
+		for i, value := range values {
+			switch name {
+			case "Cookie":
+				values[i] = redactCookies(value)
+			case "Set-Cookie":
+				values[i] = redactSetCookie(value)
+			default:
+				values[i] = Redacted
+			}
+		}
+	}
+	return header
+}
+
+// "a=1; b=2" -> "a=REDACTED; b=REDACTED"
+func redactCookies(value string) string {
+	cookies := strings.Split(value, ";")
+	for i, cookie := range cookies {
+		cookies[i] = redactSetCookie(strings.TrimSpace(cookie))
+	}
+	return strings.Join(cookies, "; ")
+}
+
+// скрывает значение cookie, атрибуты после ";" сохраняются
+func redactSetCookie(value string) string {
+	nameValue, attributes := value, ""
+	if i := strings.Index(value, ";"); i >= 0 {
+		nameValue, attributes = value[:i], value[i:]
+	}
+	i := strings.Index(nameValue, "=")
+	if i < 0 {
+		return value
+	}
+	return nameValue[:i+1] + Redacted + attributes
+}
+
+// скрывает непустые значения полей в JSON; тело, которое не является JSON, не изменяется
+func (r redactor) body(body []byte) []byte {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return body
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil || decoder.More() {
+		return body
+	}
+	redacted, err := json.Marshal(r.value(v))
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+func (r redactor) value(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if r.fields[strings.ToLower(key)] && value != nil && value != "" {
+				v[key] = Redacted
+				continue
+			}
+			v[key] = r.value(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = r.value(value)
+		}
+	}
+	return v
+}
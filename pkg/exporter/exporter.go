@@ -0,0 +1,278 @@
+// Package exporter выгружает расписания и временные затраты СУФТ плоскими строками
+// в CSV, XLSX, JSON и Markdown
+package exporter
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"suftsdk/internal/xlsx"
+	"suftsdk/pkg/api"
+	"time"
+)
+
+type Format string
+
+const (
+	CSV      Format = "csv"
+	XLSX     Format = "xlsx"
+	JSON     Format = "json"
+	Markdown Format = "markdown"
+)
+
+// ErrUnknownFormat возвращается для неподдерживаемого формата выгрузки
+var ErrUnknownFormat = errors.New("unknown export format")
+
+// Valid сообщает, поддерживается ли формат
+func (f Format) Valid() bool {
+	switch f {
+	case CSV, XLSX, JSON, Markdown:
+		return true
+	}
+	return false
+}
+
+// строка выгрузки: временная затрата вместе с расписанием, периодом и автором
+type Row struct {
+	ScheduleId           int            `json:"scheduleId"`
+	ScheduleStatus       api.StatusCode `json:"scheduleStatus"`
+	PeriodId             int            `json:"periodId"`
+	WeekNumber           int            `json:"weekNumber"`
+	StartDate            api.Date       `json:"startDate"`
+	EndDate              api.Date       `json:"endDate"`
+	AuthorId             int            `json:"authorId"`
+	AuthorName           string         `json:"authorName"`
+	AuthorEmail          string         `json:"authorEmail"`
+	LoggingTimeId        int            `json:"loggingTimeId"`
+	ProjectId            int            `json:"projectId"`
+	WorkKindId           int            `json:"workKindId"`
+	Task                 string         `json:"task"`
+	CommentEmployee      string         `json:"commentEmployee"`
+	CommentAdminEmployee string         `json:"commentAdminEmployee"`
+	StatusCode           api.StatusCode `json:"statusCode"`
+	Day1Time             float64        `json:"day1Time"`
+	Day2Time             float64        `json:"day2Time"`
+	Day3Time             float64        `json:"day3Time"`
+	Day4Time             float64        `json:"day4Time"`
+	Day5Time             float64        `json:"day5Time"`
+	Day6Time             float64        `json:"day6Time"`
+	Day7Time             float64        `json:"day7Time"`
+	TotalHours           float64        `json:"totalHours"`
+}
+
+// опции выбора расписаний для выгрузки
+type Options struct {
+	// расписания по id; если список пуст, выбираются расписания по датам
+	ScheduleIds []api.ScheduleId
+	// расписания, период которых пересекается с [From, To]; нулевая дата не ограничивает интервал
+	From time.Time
+	To   time.Time
+	// роль, с которой запрашиваются расписания, по умолчанию creator
+	CreatorApprover api.Role
+}
+
+// Rows возвращает строки выгрузки для временных затрат расписания
+func Rows(schedule *api.Schedule, loggingTimes []*api.LoggingTime) []Row {
+	rows := make([]Row, 0, len(loggingTimes))
+	author := schedule.Author
+	name := strings.Join(strings.Fields(strings.Join([]string{author.LastName, author.FirstName, author.MiddleName}, " ")), " ")
+	for _, l := range loggingTimes {
+		rows = append(rows, Row{
+			ScheduleId:           schedule.Id,
+			ScheduleStatus:       schedule.StatusCode,
+			PeriodId:             schedule.Period.Id,
+			WeekNumber:           schedule.Period.WeekNumber,
+			StartDate:            schedule.Period.StartDate,
+			EndDate:              schedule.Period.EndDate,
+			AuthorId:             author.Id,
+			AuthorName:           name,
+			AuthorEmail:          author.Email,
+			LoggingTimeId:        l.Id,
+			ProjectId:            l.ProjectId,
+			WorkKindId:           l.WorkKindId,
+			Task:                 l.Task,
+			CommentEmployee:      l.CommentEmployee,
+			CommentAdminEmployee: l.CommentAdminEmployee,
+			StatusCode:           l.StatusCode,
+			Day1Time:             l.Day1Time,
+			Day2Time:             l.Day2Time,
+			Day3Time:             l.Day3Time,
+			Day4Time:             l.Day4Time,
+			Day5Time:             l.Day5Time,
+			Day6Time:             l.Day6Time,
+			Day7Time:             l.Day7Time,
+			TotalHours:           l.TotalHours(),
+		})
+	}
+	return rows
+}
+
+// Collect запрашивает расписания и их временные затраты и возвращает строки выгрузки
+func Collect(client api.API, options *Options) ([]Row, error) {
+	return CollectContext(context.Background(), client, options)
+}
+
+func CollectContext(ctx context.Context, client api.API, options *Options) ([]Row, error) {
+	opts := Options{}
+	if options != nil {
+		opts = *options
+	}
+	schedules, err := schedules(ctx, client, &opts)
+	if err != nil {
+		return nil, err
+	}
+	rows := []Row{}
+	for _, schedule := range schedules {
+		loggingTimes, err := api.AllLoggingTimesContext(ctx, client, api.ScheduleId(schedule.Id), nil)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, Rows(schedule, loggingTimes)...)
+	}
+	return rows, nil
+}
+
+func schedules(ctx context.Context, client api.API, opts *Options) ([]*api.Schedule, error) {
+	if len(opts.ScheduleIds) > 0 {
+		schedules := make([]*api.Schedule, 0, len(opts.ScheduleIds))
+		for _, id := range opts.ScheduleIds {
+			schedule, err := client.DetailScheduleContext(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			schedules = append(schedules, schedule)
+		}
+		return schedules, nil
+	}
+	all, err := api.AllSchedulesContext(ctx, client, &api.OptionsS{Size: 20, CreatorApprover: opts.CreatorApprover})
+	if err != nil {
+		return nil, err
+	}
+	schedules := []*api.Schedule{}
+	for _, schedule := range all {
+		if overlaps(&schedule.Period, opts.From, opts.To) {
+			schedules = append(schedules, schedule)
+		}
+	}
+	return schedules, nil
+}
+
+// проверяет, пересекается ли период с интервалом [from, to]
+func overlaps(period *api.Period, from time.Time, to time.Time) bool {
+	if !to.IsZero() && !period.StartDate.IsZero() && period.StartDate.After(api.DateOf(to).Time) {
+		return false
+	}
+	if !from.IsZero() && !period.EndDate.IsZero() && period.EndDate.Before(api.DateOf(from).Time) {
+		return false
+	}
+	return true
+}
+
+// столбец табличной выгрузки
+type column struct {
+	title string
+	value func(r *Row) interface{}
+}
+
+var columns = []column{
+	{"Расписание", func(r *Row) interface{} { return r.ScheduleId }},
+	{"Статус расписания", func(r *Row) interface{} { return r.ScheduleStatus.LabelRu() }},
+	{"Неделя", func(r *Row) interface{} { return r.WeekNumber }},
+	{"Начало", func(r *Row) interface{} { return r.StartDate.String() }},
+	{"Конец", func(r *Row) interface{} { return r.EndDate.String() }},
+	{"Сотрудник", func(r *Row) interface{} { return r.AuthorName }},
+	{"Email", func(r *Row) interface{} { return r.AuthorEmail }},
+	{"Затрата", func(r *Row) interface{} { return r.LoggingTimeId }},
+	{"Проект", func(r *Row) interface{} { return r.ProjectId }},
+	{"Вид работ", func(r *Row) interface{} { return r.WorkKindId }},
+	{"Задача", func(r *Row) interface{} { return r.Task }},
+	{"Комментарий", func(r *Row) interface{} { return r.CommentEmployee }},
+	{"Комментарий руководителя", func(r *Row) interface{} { return r.CommentAdminEmployee }},
+	{"Статус", func(r *Row) interface{} { return r.StatusCode.LabelRu() }},
+	{"Пн", func(r *Row) interface{} { return r.Day1Time }},
+	{"Вт", func(r *Row) interface{} { return r.Day2Time }},
+	{"Ср", func(r *Row) interface{} { return r.Day3Time }},
+	{"Чт", func(r *Row) interface{} { return r.Day4Time }},
+	{"Пт", func(r *Row) interface{} { return r.Day5Time }},
+	{"Сб", func(r *Row) interface{} { return r.Day6Time }},
+	{"Вс", func(r *Row) interface{} { return r.Day7Time }},
+	{"Итого", func(r *Row) interface{} { return r.TotalHours }},
+}
+
+func table(rows []Row) [][]interface{} {
+	header := make([]interface{}, len(columns))
+	for i, c := range columns {
+		header[i] = c.title
+	}
+	table := [][]interface{}{header}
+	for i := range rows {
+		record := make([]interface{}, len(columns))
+		for j, c := range columns {
+			record[j] = c.value(&rows[i])
+		}
+		table = append(table, record)
+	}
+	return table
+}
+
+func format(value interface{}) string {
+	if f, ok := value.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+// Write записывает строки в выбранном формате
+func Write(w io.Writer, f Format, rows []Row) error {
+	switch f {
+	case CSV:
+		return writeCSV(w, rows)
+	case XLSX:
+		return xlsx.WriteRows(w, "СУФТ", table(rows))
+	case JSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(rows)
+	case Markdown:
+		return writeMarkdown(w, rows)
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownFormat, f)
+}
+
+func writeCSV(w io.Writer, rows []Row) error {
+	writer := csv.NewWriter(w)
+	for _, record := range table(rows) {
+		values := make([]string, len(record))
+		for i, value := range record {
+			values[i] = format(value)
+		}
+		if err := writer.Write(values); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeMarkdown(w io.Writer, rows []Row) error {
+	var b strings.Builder
+	for i, record := range table(rows) {
+		b.WriteString("|")
+		for _, value := range record {
+			cell := strings.ReplaceAll(format(value), "|", `\|`)
+			cell = strings.ReplaceAll(cell, "\n", " ")
+			b.WriteString(" " + cell + " |")
+		}
+		b.WriteString("\n")
+		if i == 0 {
+			b.WriteString("|" + strings.Repeat(" --- |", len(record)) + "\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
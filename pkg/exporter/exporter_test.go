@@ -0,0 +1,122 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"suftsdk/internal/xlsx"
+	"suftsdk/pkg/api"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var exportSchedule = &api.Schedule{
+	Id:         777,
+	Author:     api.Employee{Id: 5, Email: "ivanov@example.com", FirstName: "Иван", LastName: "Иванов"},
+	Period:     api.Period{Id: 354, WeekNumber: 7, StartDate: api.NewDate(2021, 2, 15), EndDate: api.NewDate(2021, 2, 21)},
+	StatusCode: api.ToApprove,
+}
+
+var exportLoggingTimes = []*api.LoggingTime{
+	{Id: 1, ProjectId: 1, WorkKindId: 21, Task: "task | 1", Day1Time: 8, Day2Time: 7.5, StatusCode: api.ToApprove},
+}
+
+func TestRows(t *testing.T) {
+	rows := Rows(exportSchedule, exportLoggingTimes)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "Иванов Иван", rows[0].AuthorName)
+	assert.Equal(t, 354, rows[0].PeriodId)
+	assert.Equal(t, 15.5, rows[0].TotalHours)
+}
+
+func TestWriteCSV(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, Write(&out, CSV, Rows(exportSchedule, exportLoggingTimes)))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[0], "Расписание,Статус расписания,Неделя,Начало"))
+	assert.Equal(t, "777,На утверждении,7,2021-02-15,2021-02-21,Иванов Иван,ivanov@example.com,1,1,21,task | 1,,,На утверждении,8,7.5,0,0,0,0,0,15.5", lines[1])
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, Write(&out, Markdown, Rows(exportSchedule, exportLoggingTimes)))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[1], "| --- | --- |"))
+	assert.Contains(t, lines[2], `| task \| 1 |`)
+}
+
+func TestWriteJSON(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, Write(&out, JSON, Rows(exportSchedule, exportLoggingTimes)))
+	rows := []Row{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &rows))
+	assert.Equal(t, Rows(exportSchedule, exportLoggingTimes), rows)
+	assert.Contains(t, out.String(), "\n  {\n")
+}
+
+func TestWriteXLSX(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, Write(&out, XLSX, Rows(exportSchedule, exportLoggingTimes)))
+	r := bytes.NewReader(out.Bytes())
+	rows, err := xlsx.ReadRows(r, r.Size())
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "Итого", rows[0][len(rows[0])-1])
+	assert.Equal(t, "15.5", rows[1][len(rows[1])-1])
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	assert.False(t, Format("pdf").Valid())
+	assert.True(t, Markdown.Valid())
+	require.ErrorIs(t, Write(&bytes.Buffer{}, "pdf", nil), ErrUnknownFormat)
+}
+
+// клиент с одним расписанием на каждую неделю февраля 2021
+type exportClient struct {
+	api.API
+	requested []api.ScheduleId
+}
+
+func (c *exportClient) SchedulesContext(ctx context.Context, options *api.OptionsS) ([]*api.Schedule, error) {
+	schedules := []*api.Schedule{}
+	for i := 0; i < 4; i++ {
+		start := api.NewDate(2021, 2, 1).AddDays(7 * i)
+		schedules = append(schedules, &api.Schedule{Id: 100 + i, Period: api.Period{StartDate: start, EndDate: start.AddDays(6)}})
+	}
+	return schedules, nil
+}
+
+func (c *exportClient) DetailScheduleContext(ctx context.Context, scheduleId api.ScheduleId) (*api.Schedule, error) {
+	return &api.Schedule{Id: int(scheduleId)}, nil
+}
+
+func (c *exportClient) LoggingTimeListContext(ctx context.Context, scheduleId api.ScheduleId, options *api.OptionsLT) ([]*api.LoggingTime, error) {
+	c.requested = append(c.requested, scheduleId)
+	return []*api.LoggingTime{{Id: int(scheduleId) * 10, Day1Time: 8}}, nil
+}
+
+func TestCollectByDates(t *testing.T) {
+	client := &exportClient{}
+	rows, err := Collect(client, &Options{
+		From: time.Date(2021, 2, 10, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2021, 2, 16, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []api.ScheduleId{101, 102}, client.requested)
+	require.Len(t, rows, 2)
+	assert.Equal(t, 1010, rows[0].LoggingTimeId)
+}
+
+func TestCollectByIds(t *testing.T) {
+	client := &exportClient{}
+	rows, err := Collect(client, &Options{ScheduleIds: []api.ScheduleId{5, 7}})
+	require.NoError(t, err)
+	assert.Equal(t, []api.ScheduleId{5, 7}, client.requested)
+	assert.Len(t, rows, 2)
+}
@@ -0,0 +1,386 @@
+// Package importer читает временные затраты из таблиц CSV и XLSX
+// и добавляет их в расписание СУФТ
+package importer
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"suftsdk/internal/xlsx"
+	"suftsdk/pkg/api"
+	"time"
+)
+
+type Format string
+
+const (
+	CSV  Format = "csv"
+	XLSX Format = "xlsx"
+)
+
+// ErrUnknownFormat возвращается, если формат файла не удалось определить
+var ErrUnknownFormat = errors.New("unknown import format")
+
+// ErrNoPeriod возвращается, если в таблице есть столбец даты, а период расписания не передан
+var ErrNoPeriod = errors.New("для строк с датой нужен период расписания")
+
+// соответствие полей временной затраты заголовкам столбцов таблицы.
+// Заголовки сравниваются без учёта регистра и пробелов по краям, пустой заголовок - поля нет в таблице
+type Mapping struct {
+	Project  string `json:"project"`
+	WorkKind string `json:"workKind"`
+	Task     string `json:"task"`
+	Comment  string `json:"comment"`
+	// столбцы часов по дням, от Day1Time до Day7Time
+	Days [7]string `json:"days"`
+	// столбцы даты и часов. Если в таблице есть столбец даты, каждая строка содержит
+	// часы за одну дату, а строки с одинаковыми проектом, видом работ, задачей
+	// и комментарием объединяются в одну временную затрату
+	Date  string `json:"date"`
+	Hours string `json:"hours"`
+}
+
+// DefaultMapping возвращает соответствие с именами полей из JSON API
+func DefaultMapping() Mapping {
+	return Mapping{
+		Project:  "projectId",
+		WorkKind: "workKindId",
+		Task:     "task",
+		Comment:  "commentEmployee",
+		Days:     [7]string{"day1Time", "day2Time", "day3Time", "day4Time", "day5Time", "day6Time", "day7Time"},
+		Date:     "date",
+		Hours:    "hours",
+	}
+}
+
+// опции чтения таблицы
+type Options struct {
+	Format Format
+	// nil - DefaultMapping
+	Mapping *Mapping
+	// разделитель CSV, по умолчанию запятая
+	Comma rune
+	// период расписания, нужен для строк с датой
+	Period *api.Period
+	// разбор, округление и шаг проверки часов; nil - часы без округления
+	Hours *api.HoursParser
+}
+
+// строка таблицы, прочитанная как временная затрата
+type Row struct {
+	// номер строки в файле, начиная с 1
+	Line        int
+	LoggingTime *api.AddLoggingTime
+	// ошибка разбора или проверки, такая строка не добавляется
+	Err error
+}
+
+// FormatFromPath определяет формат по расширению файла
+func FormatFromPath(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return CSV, nil
+	case ".xlsx":
+		return XLSX, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnknownFormat, path)
+}
+
+// ReadFile читает временные затраты из файла. Если формат не задан, он определяется по расширению
+func ReadFile(path string, options *Options) ([]Row, error) {
+	opts := Options{}
+	if options != nil {
+		opts = *options
+	}
+	if opts.Format == "" {
+		format, err := FormatFromPath(path)
+		if err != nil {
+			return nil, err
+		}
+		opts.Format = format
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Read(bytes.NewReader(data), &opts)
+}
+
+// Read читает временные затраты из таблицы. Первая строка таблицы - заголовки столбцов
+func Read(r io.Reader, options *Options) ([]Row, error) {
+	opts := Options{Format: CSV}
+	if options != nil {
+		opts = *options
+	}
+	mapping := DefaultMapping()
+	if opts.Mapping != nil {
+		mapping = *opts.Mapping
+	}
+	records, lines, err := readRecords(r, &opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return []Row{}, nil
+	}
+	columns := newColumns(records[0], mapping)
+	if columns.date >= 0 {
+		if opts.Period == nil {
+			return nil, ErrNoPeriod
+		}
+		return readDateRows(records[1:], lines[1:], columns, opts.Period, hoursParser(opts)), nil
+	}
+	rows := []Row{}
+	for i, record := range records[1:] {
+		if isBlank(record) {
+			continue
+		}
+		row := Row{Line: lines[i+1], LoggingTime: &api.AddLoggingTime{}}
+		row.Err = columns.fill(row.LoggingTime, record)
+		for day, column := range columns.days {
+			if row.Err != nil {
+				break
+			}
+			var hours float64
+			hours, row.Err = hoursParser(opts).Parse(columns.value(record, column))
+			row.LoggingTime.SetHours(time.Weekday((day+1)%7), hours)
+		}
+		rows = append(rows, validated(row, hoursParser(opts)))
+	}
+	return rows, nil
+}
+
+// readRecords возвращает строки таблицы и номера их первых строк в файле
+func readRecords(r io.Reader, opts *Options) ([][]string, []int, error) {
+	switch opts.Format {
+	case CSV:
+		return readCSV(r, opts.Comma)
+	case XLSX:
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		records, err := xlsx.ReadRows(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, nil, err
+		}
+		// пустые строки листа сохраняются, номер строки совпадает с индексом
+		lines := make([]int, len(records))
+		for i := range lines {
+			lines[i] = i + 1
+		}
+		return records, lines, nil
+	}
+	return nil, nil, fmt.Errorf("%w: %s", ErrUnknownFormat, opts.Format)
+}
+
+// encoding/csv пропускает пустые строки, поэтому номер строки берётся из позиции записи
+func readCSV(r io.Reader, comma rune) ([][]string, []int, error) {
+	reader := csv.NewReader(r)
+	if comma != 0 {
+		reader.Comma = comma
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, lines := [][]string{}, []int{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return records, lines, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		records = append(records, record)
+		lines = append(lines, line)
+	}
+}
+
+// строки с датой и часами объединяются по проекту, виду работ, задаче и комментарию
+func readDateRows(records [][]string, lines []int, columns columns, period *api.Period, parser api.HoursParser) []Row {
+	rows := []Row{}
+	index := map[string]int{}
+	for i, record := range records {
+		if isBlank(record) {
+			continue
+		}
+		row := Row{Line: lines[i], LoggingTime: &api.AddLoggingTime{}}
+		if row.Err = columns.fill(row.LoggingTime, record); row.Err != nil {
+			rows = append(rows, row)
+			continue
+		}
+		date, err := parseDate(columns.value(record, columns.date))
+		if err != nil {
+			row.Err = err
+			rows = append(rows, row)
+			continue
+		}
+		hours, err := parser.Parse(columns.value(record, columns.hours))
+		if err != nil {
+			row.Err = err
+			rows = append(rows, row)
+			continue
+		}
+		key := fmt.Sprint(row.LoggingTime.ProjectId, "\x00", row.LoggingTime.WorkKindId, "\x00", row.LoggingTime.Task, "\x00", row.LoggingTime.CommentEmployee)
+		if j, ok := index[key]; ok {
+			row = rows[j]
+		} else {
+			index[key] = len(rows)
+			rows = append(rows, row)
+		}
+		current, err := row.LoggingTime.HoursOn(period, date)
+		if err == nil {
+			err = row.LoggingTime.SetHoursOn(period, date, current+hours)
+		}
+		if err != nil && row.Err == nil {
+			rows[index[key]].Err = fmt.Errorf("строка %d: %w", lines[i], err)
+		}
+	}
+	for i := range rows {
+		rows[i] = validated(rows[i], parser)
+	}
+	return rows
+}
+
+// часы проверяются на кратность шагу округления, без округления шаг не проверяется
+func validated(row Row, parser api.HoursParser) Row {
+	if row.Err == nil {
+		rules := api.DefaultValidationRules()
+		rules.HourStep = parser.Step
+		row.Err = row.LoggingTime.ValidateWith(rules)
+	}
+	return row
+}
+
+// номера столбцов таблицы для полей временной затраты, -1 - столбца нет
+type columns struct {
+	project, workKind, task, comment, date, hours int
+	days                                          [7]int
+}
+
+func newColumns(header []string, mapping Mapping) columns {
+	find := func(name string) int {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return -1
+		}
+		for i, title := range header {
+			if strings.EqualFold(strings.TrimSpace(title), name) {
+				return i
+			}
+		}
+		return -1
+	}
+	c := columns{
+		project:  find(mapping.Project),
+		workKind: find(mapping.WorkKind),
+		task:     find(mapping.Task),
+		comment:  find(mapping.Comment),
+		date:     find(mapping.Date),
+		hours:    find(mapping.Hours),
+	}
+	for i, name := range mapping.Days {
+		c.days[i] = find(name)
+	}
+	return c
+}
+
+func (c columns) value(record []string, column int) string {
+	if column < 0 || column >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[column])
+}
+
+// заполняет проект, вид работ, задачу и комментарий
+func (c columns) fill(loggingTime *api.AddLoggingTime, record []string) error {
+	var err error
+	loggingTime.ProjectId, err = parseId("проекта", c.value(record, c.project))
+	if err != nil {
+		return err
+	}
+	loggingTime.WorkKindId, err = parseId("вида работ", c.value(record, c.workKind))
+	if err != nil {
+		return err
+	}
+	loggingTime.Task = c.value(record, c.task)
+	loggingTime.CommentEmployee = c.value(record, c.comment)
+	return nil
+}
+
+func isBlank(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func parseId(name string, value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	id, err := strconv.Atoi(strings.TrimSuffix(value, ".0"))
+	if err != nil {
+		return 0, fmt.Errorf("неверный id %s %q", name, value)
+	}
+	return id, nil
+}
+
+// часы можно указывать числом с точкой или запятой, временем 7:30 или с единицами 7h30m
+func hoursParser(opts Options) api.HoursParser {
+	if opts.Hours == nil {
+		return api.HoursParser{}
+	}
+	return *opts.Hours
+}
+
+// дата в формате ГГГГ-ММ-ДД, ДД.ММ.ГГГГ или порядковым номером дня, как её хранит XLSX
+func parseDate(value string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", "02.01.2006"} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	if serial, err := strconv.ParseFloat(value, 64); err == nil && serial > 0 {
+		return time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC).AddDate(0, 0, int(serial)), nil
+	}
+	return time.Time{}, fmt.Errorf("неверная дата %q, ожидается ГГГГ-ММ-ДД или ДД.ММ.ГГГГ", value)
+}
+
+// результат добавления одной строки
+type Result struct {
+	Row     Row
+	Created *api.LoggingTime
+	Err     error
+}
+
+// Submit добавляет строки без ошибок в расписание. Ошибка отдельной строки
+// записывается в результат и не прерывает добавление остальных
+func Submit(client api.API, scheduleId api.ScheduleId, rows []Row) ([]Result, error) {
+	return SubmitContext(context.Background(), client, scheduleId, rows)
+}
+
+func SubmitContext(ctx context.Context, client api.API, scheduleId api.ScheduleId, rows []Row) ([]Result, error) {
+	results := make([]Result, 0, len(rows))
+	for _, row := range rows {
+		result := Result{Row: row, Err: row.Err}
+		if row.Err == nil {
+			result.Created, result.Err = client.AddLoggingTimeContext(ctx, scheduleId, row.LoggingTime)
+			if result.Err != nil && ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
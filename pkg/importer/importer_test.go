@@ -0,0 +1,181 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"suftsdk/pkg/api"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var importPeriod = &api.Period{Id: 354, StartDate: api.NewDate(2021, 2, 15), EndDate: api.NewDate(2021, 2, 21)}
+
+func TestReadCSVDayColumns(t *testing.T) {
+	data := "projectId,workKindId,task,commentEmployee,day1Time,day2Time,day3Time,day4Time,day5Time,day6Time,day7Time\n" +
+		"1,21,task 1,comment,8,\"7,5\",,,,,\n" +
+		",,,,,,,,,,\n" +
+		"x,21,task 2,,8,,,,,,\n" +
+		"2,22,task 3,,-1,,,,,,\n"
+	rows, err := Read(strings.NewReader(data), nil)
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+
+	assert.Equal(t, 2, rows[0].Line)
+	require.NoError(t, rows[0].Err)
+	assert.Equal(t, &api.AddLoggingTime{
+		CommentEmployee: "comment",
+		Day1Time:        8,
+		Day2Time:        7.5,
+		ProjectId:       1,
+		Task:            "task 1",
+		WorkKindId:      21,
+	}, rows[0].LoggingTime)
+
+	assert.Equal(t, 4, rows[1].Line)
+	assert.EqualError(t, rows[1].Err, `неверный id проекта "x"`)
+	assert.True(t, errors.Is(rows[2].Err, api.ErrInvalidHours))
+}
+
+func TestReadCSVLineNumbers(t *testing.T) {
+	data := "projectId,workKindId,task,day1Time\n\n1,21,\"task\n1\",8\n\r\nx,21,task,8\n"
+	rows, err := Read(strings.NewReader(data), nil)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, 3, rows[0].Line)
+	assert.Equal(t, 6, rows[1].Line)
+
+	data = "projectId,workKindId,task,date,hours\n\n1,21,task,2021-02-15,8\n\n1,21,task,2021-02-30,8\n"
+	rows, err = Read(strings.NewReader(data), &Options{Format: CSV, Period: importPeriod})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, 3, rows[0].Line)
+	assert.Equal(t, 5, rows[1].Line)
+}
+
+func TestReadCSVHoursSyntax(t *testing.T) {
+	data := "projectId,workKindId,task,day1Time,day2Time,day3Time,day4Time\n" +
+		"1,21,task,1:30,7h30m,50m,1.30\n" +
+		"1,21,task,1:30,7h30m,50m,\n"
+	rows, err := Read(strings.NewReader(data), &Options{Format: CSV, Hours: &api.HoursParser{Step: 0.25}})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.ErrorIs(t, rows[0].Err, api.ErrInvalidHours)
+	require.NoError(t, rows[1].Err)
+	assert.Equal(t, 1.5, rows[1].LoggingTime.Day1Time)
+	assert.Equal(t, 7.5, rows[1].LoggingTime.Day2Time)
+	assert.Equal(t, 0.75, rows[1].LoggingTime.Day3Time)
+
+	rows, err = Read(strings.NewReader("projectId,workKindId,task,day1Time\n1,21,task,1:20\n"), nil)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.NoError(t, rows[0].Err)
+}
+
+func TestReadCSVMapping(t *testing.T) {
+	data := "Проект;Вид работ;Задача;Пн;Вт\n1;21;task;4;4\n"
+	mapping := Mapping{Project: "проект", WorkKind: "Вид работ", Task: "задача", Days: [7]string{"Пн", "Вт"}}
+	rows, err := Read(strings.NewReader(data), &Options{Format: CSV, Comma: ';', Mapping: &mapping})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.NoError(t, rows[0].Err)
+	assert.Equal(t, 8.0, rows[0].LoggingTime.TotalHours())
+}
+
+func TestReadCSVDateRows(t *testing.T) {
+	data := "projectId,workKindId,task,date,hours\n" +
+		"1,21,task,2021-02-15,8\n" +
+		"1,21,task,16.02.2021,6\n" +
+		"2,21,other,2021-02-17,4\n" +
+		"1,21,task,2021-02-16,2\n" +
+		"1,21,task,2021-03-01,2\n"
+	rows, err := Read(strings.NewReader(data), &Options{Format: CSV, Period: importPeriod})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, 2, rows[0].Line)
+	assert.Equal(t, 8.0, rows[0].LoggingTime.Day1Time)
+	assert.Equal(t, 8.0, rows[0].LoggingTime.Day2Time)
+	assert.True(t, errors.Is(rows[0].Err, api.ErrDateOutsidePeriod))
+	assert.Equal(t, 4.0, rows[1].LoggingTime.Day3Time)
+	assert.NoError(t, rows[1].Err)
+
+	_, err = Read(strings.NewReader(data), nil)
+	require.ErrorIs(t, err, ErrNoPeriod)
+}
+
+func TestReadXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, err := w.Create("xl/worksheets/sheet1.xml")
+	require.NoError(t, err)
+	_, err = f.Write([]byte(`<worksheet><sheetData>
+		<row r="1"><c r="A1" t="inlineStr"><is><t>projectId</t></is></c><c r="B1" t="inlineStr"><is><t>workKindId</t></is></c>
+		<c r="C1" t="inlineStr"><is><t>task</t></is></c><c r="D1" t="inlineStr"><is><t>date</t></is></c><c r="E1" t="inlineStr"><is><t>hours</t></is></c></row>
+		<row r="2"><c r="A2"><v>1</v></c><c r="B2"><v>21</v></c><c r="C2" t="inlineStr"><is><t>task</t></is></c><c r="D2"><v>44243</v></c><c r="E2"><v>7.5</v></c></row>
+		</sheetData></worksheet>`))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	rows, err := Read(&buf, &Options{Format: XLSX, Period: importPeriod})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.NoError(t, rows[0].Err)
+	assert.Equal(t, 7.5, rows[0].LoggingTime.Day2Time)
+}
+
+func TestFormatFromPath(t *testing.T) {
+	format, err := FormatFromPath("/tmp/week.XLSX")
+	require.NoError(t, err)
+	assert.Equal(t, XLSX, format)
+	_, err = FormatFromPath("week.txt")
+	require.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+// клиент, который добавляет временные затраты с последовательными id
+type addingClient struct {
+	api.API
+	added []*api.AddLoggingTime
+}
+
+func (c *addingClient) AddLoggingTimeContext(ctx context.Context, scheduleId api.ScheduleId, loggingTime *api.AddLoggingTime) (*api.LoggingTime, error) {
+	if loggingTime.Task == "fail" {
+		return nil, errors.New("error from AddLoggingTime method")
+	}
+	c.added = append(c.added, loggingTime)
+	return &api.LoggingTime{Id: len(c.added)}, nil
+}
+
+func TestSubmit(t *testing.T) {
+	rows := []Row{
+		{Line: 2, LoggingTime: &api.AddLoggingTime{Task: "ok"}},
+		{Line: 3, LoggingTime: &api.AddLoggingTime{Task: "invalid"}, Err: errors.New("invalid row")},
+		{Line: 4, LoggingTime: &api.AddLoggingTime{Task: "fail"}},
+	}
+	client := &addingClient{}
+	results, err := Submit(client, 777, rows)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, 1, results[0].Created.Id)
+	assert.EqualError(t, results[1].Err, "invalid row")
+	assert.Error(t, results[2].Err)
+	assert.Len(t, client.added, 1)
+
+	var out bytes.Buffer
+	require.NoError(t, WriteReport(&out, results))
+	assert.Equal(t, "строка 2: добавлена, id 1\n"+
+		"строка 3: ошибка: invalid row\n"+
+		"строка 4: ошибка: error from AddLoggingTime method\n", out.String())
+}
+
+func TestWritePreview(t *testing.T) {
+	var out bytes.Buffer
+	rows := []Row{{Line: 2, LoggingTime: &api.AddLoggingTime{ProjectId: 1, WorkKindId: 21, Task: "task", Day1Time: 8}}}
+	require.NoError(t, WritePreview(&out, rows))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[1], "2       1       21         task    8   0"))
+}
@@ -0,0 +1,42 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// WritePreview выводит прочитанные строки таблицей для проверки перед добавлением
+func WritePreview(w io.Writer, rows []Row) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Строка\tПроект\tВид работ\tЗадача\tПн\tВт\tСр\tЧт\tПт\tСб\tВс\tИтого\tОшибка")
+	for _, row := range rows {
+		l := row.LoggingTime
+		errText := ""
+		if row.Err != nil {
+			errText = row.Err.Error()
+		}
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%s\t%g\t%g\t%g\t%g\t%g\t%g\t%g\t%g\t%s\n",
+			row.Line, l.ProjectId, l.WorkKindId, l.Task,
+			l.Day1Time, l.Day2Time, l.Day3Time, l.Day4Time, l.Day5Time, l.Day6Time, l.Day7Time,
+			l.TotalHours(), errText)
+	}
+	return tw.Flush()
+}
+
+// WriteReport выводит результат добавления строк
+func WriteReport(w io.Writer, results []Result) error {
+	for _, result := range results {
+		var err error
+		switch {
+		case result.Err != nil:
+			_, err = fmt.Fprintf(w, "строка %d: ошибка: %s\n", result.Row.Line, result.Err)
+		default:
+			_, err = fmt.Fprintf(w, "строка %d: добавлена, id %d\n", result.Row.Line, result.Created.Id)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
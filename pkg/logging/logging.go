@@ -0,0 +1,128 @@
+// Package logging - интерфейс структурированного журнала, через который SDK
+// сообщает о запросах к СУФТ, повторах, обновлении токенов и ошибках.
+// По умолчанию SDK ничего не пишет; чтобы видеть записи, передайте Logger
+// в api.OptionsNC, например logging.New(os.Stderr, logging.Debug), или
+// адаптер к используемой в приложении библиотеке журналирования:
+//
+//	logger := logging.LoggerFunc(func(level logging.Level, msg string, fields ...logging.Field) {
+//		// запись в журнал приложения
+//	})
+package logging
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Level int
+
+const (
+	Debug Level = iota
+	Info
+	Warn
+	Error
+)
+
+var levelNames = [...]string{"debug", "info", "warn", "error"}
+
+func (l Level) String() string {
+	if l < Debug || l > Error {
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+	return levelNames[l]
+}
+
+// ParseLevel разбирает уровень по названию: debug, info, warn или error
+func ParseLevel(s string) (Level, error) {
+	for i, name := range levelNames {
+		if strings.EqualFold(s, name) {
+			return Level(i), nil
+		}
+	}
+	return Debug, fmt.Errorf("unknown log level %q, expected debug, info, warn or error", s)
+}
+
+// имена полей, которые передаёт SDK
+const (
+	// HTTP-метод запроса
+	FieldMethod = "method"
+	// URN запроса относительно адреса API
+	FieldURN = "urn"
+	// HTTP-статус ответа
+	FieldStatus = "status"
+	// длительность запроса, time.Duration
+	FieldDuration = "duration"
+	// номер попытки при повторах, начиная с 1
+	FieldAttempt = "attempt"
+	// ошибка
+	FieldError = "error"
+)
+
+// поле записи журнала
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Logger получает записи журнала. Реализация должна допускать вызовы из нескольких горутин
+type Logger interface {
+	Log(level Level, msg string, fields ...Field)
+}
+
+// LoggerFunc позволяет использовать функцию как Logger
+type LoggerFunc func(level Level, msg string, fields ...Field)
+
+func (f LoggerFunc) Log(level Level, msg string, fields ...Field) {
+	f(level, msg, fields...)
+}
+
+// Nop отбрасывает все записи, используется по умолчанию
+var Nop Logger = LoggerFunc(func(Level, string, ...Field) {})
+
+type textLogger struct {
+	mu    sync.Mutex
+	w     io.Writer
+	level Level
+}
+
+// New возвращает Logger, который пишет в w записи уровня level и выше, по одной в строке:
+//
+//	2021-02-15T10:00:00Z level=debug msg=request method=GET urn=api/v1/schedules status=200 duration=35ms
+func New(w io.Writer, level Level) Logger {
+	return &textLogger{w: w, level: level}
+}
+
+func (l *textLogger) Log(level Level, msg string, fields ...Field) {
+	if level < l.level {
+		return
+	}
+	b := &strings.Builder{}
+	b.WriteString(time.Now().UTC().Format(time.RFC3339))
+	b.WriteString(" level=")
+	b.WriteString(level.String())
+	b.WriteString(" msg=")
+	b.WriteString(quote(msg))
+	for _, field := range fields {
+		b.WriteByte(' ')
+		b.WriteString(field.Key)
+		b.WriteByte('=')
+		b.WriteString(quote(fmt.Sprint(field.Value)))
+	}
+	b.WriteByte('\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = io.WriteString(l.w, b.String())
+}
+
+// значения с пробелами, кавычками и "=" берутся в кавычки
+func quote(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\r\n\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
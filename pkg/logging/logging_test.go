@@ -0,0 +1,48 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, Info)
+	logger.Log(Debug, "request")
+	logger.Log(Warn, "request failed",
+		Field{Key: FieldMethod, Value: "GET"},
+		Field{Key: FieldDuration, Value: 35 * time.Millisecond},
+		Field{Key: FieldError, Value: errors.New("connection reset")},
+	)
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 1)
+	assert.Regexp(t, `^\d{4}-\d\d-\d\dT\S+ level=warn msg="request failed" method=GET duration=35ms error="connection reset"$`, lines[0])
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel("WARN")
+	require.NoError(t, err)
+	assert.Equal(t, Warn, level)
+	assert.Equal(t, "warn", level.String())
+
+	_, err = ParseLevel("trace")
+	assert.Error(t, err)
+	assert.Equal(t, "level(7)", Level(7).String())
+}
+
+func TestLoggerFunc(t *testing.T) {
+	var got []string
+	logger := LoggerFunc(func(level Level, msg string, fields ...Field) {
+		got = append(got, level.String()+" "+msg)
+	})
+	logger.Log(Error, "boom")
+	Nop.Log(Error, "ignored")
+	assert.Equal(t, []string{"error boom"}, got)
+}
@@ -0,0 +1,545 @@
+package suftfake
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"suftsdk/pkg/api"
+)
+
+// Client - реализация api.API, работающая с состоянием Server от имени сотрудника
+type Client struct {
+	server     *Server
+	employeeId int
+}
+
+var _ api.API = (*Client)(nil)
+
+// EmployeeId возвращает id сотрудника, от имени которого работает клиент
+func (c *Client) EmployeeId() int {
+	return c.employeeId
+}
+
+// начинает вызов и блокирует состояние сервера; при ошибке блокировка снимается
+func (c *Client) lock(ctx context.Context, method string, httpMethod string, URN string) (*employee, error) {
+	if err := c.server.begin(ctx, c.employeeId, method); err != nil {
+		return nil, err
+	}
+	c.server.mu.Lock()
+	me, ok := c.server.employees[c.employeeId]
+	if !ok {
+		c.server.mu.Unlock()
+		return nil, newError(http.StatusUnauthorized, httpMethod, URN, "Unknown employee %d", c.employeeId)
+	}
+	return me, nil
+}
+
+func (c *Client) unlock() {
+	c.server.mu.Unlock()
+}
+
+// approverOf сообщает, согласует ли сотрудник me затраты автора расписания
+func (c *Client) approverOf(me *employee, sched *schedule) bool {
+	author, ok := c.server.employees[sched.Author.Id]
+	return ok && author.approverId == me.Id
+}
+
+// расписание, доступное сотруднику как автору или согласующему
+func (c *Client) visibleSchedule(me *employee, scheduleId api.ScheduleId, httpMethod string, URN string) (*schedule, error) {
+	sched, ok := c.server.schedules[scheduleId]
+	if !ok {
+		return nil, newError(http.StatusNotFound, httpMethod, URN, "Schedule %d not found", scheduleId)
+	}
+	if sched.Author.Id != me.Id && !c.approverOf(me, sched) {
+		return nil, newError(http.StatusForbidden, httpMethod, URN, "Access to schedule %d denied", scheduleId)
+	}
+	return sched, nil
+}
+
+// расписание, которое может изменять только его автор
+func (c *Client) ownSchedule(me *employee, scheduleId api.ScheduleId, httpMethod string, URN string) (*schedule, error) {
+	sched, err := c.visibleSchedule(me, scheduleId, httpMethod, URN)
+	if err != nil {
+		return nil, err
+	}
+	if sched.Author.Id != me.Id {
+		return nil, newError(http.StatusForbidden, httpMethod, URN, "Only the author can change schedule %d", scheduleId)
+	}
+	return sched, nil
+}
+
+func findLoggingTime(sched *schedule, loggingTimeId api.LoggingTimeId) (int, *api.LoggingTime) {
+	for i, loggingTime := range sched.loggingTimes {
+		if loggingTime.Id == int(loggingTimeId) {
+			return i, loggingTime
+		}
+	}
+	return -1, nil
+}
+
+// копии объектов связываются с клиентом, чтобы их методы работали через него
+func (c *Client) copySchedule(sched *schedule) *api.Schedule {
+	scheduleCopy := sched.Schedule
+	scheduleCopy.SetClient(c)
+	return &scheduleCopy
+}
+
+func (c *Client) copyLoggingTime(scheduleId api.ScheduleId, loggingTime *api.LoggingTime) *api.LoggingTime {
+	loggingTimeCopy := *loggingTime
+	loggingTimeCopy.SetClient(c, scheduleId)
+	return &loggingTimeCopy
+}
+
+func (c *Client) Schedules(options *api.OptionsS) ([]*api.Schedule, error) {
+	return c.SchedulesContext(context.Background(), options)
+}
+
+func (c *Client) SchedulesContext(ctx context.Context, options *api.OptionsS) ([]*api.Schedule, error) {
+	opts := api.OptionsS{CreatorApprover: api.Creator}
+	if options != nil {
+		opts = *options
+		if opts.CreatorApprover == "" {
+			opts.CreatorApprover = api.Creator
+		}
+	}
+	URN := fmt.Sprint(api.SchedulesURN, "?creatorApprover=", opts.CreatorApprover)
+	me, err := c.lock(ctx, "Schedules", http.MethodGet, URN)
+	if err != nil {
+		return nil, err
+	}
+	defer c.unlock()
+	if opts.CreatorApprover != api.Creator && opts.CreatorApprover != api.Approver {
+		return nil, newError(http.StatusBadRequest, http.MethodGet, URN, "Unknown role %s", opts.CreatorApprover)
+	}
+	schedules := []*api.Schedule{}
+	for _, sched := range c.server.schedules {
+		if opts.CreatorApprover == api.Creator && sched.Author.Id == me.Id ||
+			opts.CreatorApprover == api.Approver && c.approverOf(me, sched) {
+			schedules = append(schedules, c.copySchedule(sched))
+		}
+	}
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].Id < schedules[j].Id })
+	start, end := pageBounds(len(schedules), opts.Page, opts.Size)
+	return schedules[start:end], nil
+}
+
+func (c *Client) AddSchedule(periodId api.PeriodId) (*api.Schedule, error) {
+	return c.AddScheduleContext(context.Background(), periodId)
+}
+
+func (c *Client) AddScheduleContext(ctx context.Context, periodId api.PeriodId) (*api.Schedule, error) {
+	URN := api.SchedulesURN
+	me, err := c.lock(ctx, "AddSchedule", http.MethodPost, URN)
+	if err != nil {
+		return nil, err
+	}
+	defer c.unlock()
+	var period *api.Period
+	for i := range c.server.periods {
+		if c.server.periods[i].Id == int(periodId) {
+			period = &c.server.periods[i]
+		}
+	}
+	if period == nil {
+		return nil, newError(http.StatusNotFound, http.MethodPost, URN, "Period %d not found", periodId)
+	}
+	for _, sched := range c.server.schedules {
+		if sched.Author.Id == me.Id && sched.Period.Id == period.Id {
+			return nil, newError(http.StatusConflict, http.MethodPost, URN, "Schedule for period %d already exists", periodId)
+		}
+	}
+	sched := &schedule{Schedule: api.Schedule{
+		Author:     me.Employee,
+		Id:         c.server.nextId(),
+		Period:     *period,
+		StatusCode: api.Created,
+	}}
+	c.server.schedules[api.ScheduleId(sched.Id)] = sched
+	return c.copySchedule(sched), nil
+}
+
+func (c *Client) DetailSchedule(scheduleId api.ScheduleId) (*api.Schedule, error) {
+	return c.DetailScheduleContext(context.Background(), scheduleId)
+}
+
+func (c *Client) DetailScheduleContext(ctx context.Context, scheduleId api.ScheduleId) (*api.Schedule, error) {
+	URN := fmt.Sprintf("%s/%d", api.SchedulesURN, scheduleId)
+	me, err := c.lock(ctx, "DetailSchedule", http.MethodGet, URN)
+	if err != nil {
+		return nil, err
+	}
+	defer c.unlock()
+	sched, err := c.visibleSchedule(me, scheduleId, http.MethodGet, URN)
+	if err != nil {
+		return nil, err
+	}
+	return c.copySchedule(sched), nil
+}
+
+func (c *Client) LoggingTimeList(scheduleId api.ScheduleId, options *api.OptionsLT) ([]*api.LoggingTime, error) {
+	return c.LoggingTimeListContext(context.Background(), scheduleId, options)
+}
+
+func (c *Client) LoggingTimeListContext(ctx context.Context, scheduleId api.ScheduleId, options *api.OptionsLT) ([]*api.LoggingTime, error) {
+	URN := fmt.Sprintf("%s/%d/%s", api.SchedulesURN, scheduleId, api.LoggingTimeURN)
+	me, err := c.lock(ctx, "LoggingTimeList", http.MethodGet, URN)
+	if err != nil {
+		return nil, err
+	}
+	defer c.unlock()
+	sched, err := c.visibleSchedule(me, scheduleId, http.MethodGet, URN)
+	if err != nil {
+		return nil, err
+	}
+	opts := api.OptionsLT{}
+	if options != nil {
+		opts = *options
+	}
+	start, end := pageBounds(len(sched.loggingTimes), opts.Page, opts.Size)
+	loggingTimes := make([]*api.LoggingTime, 0, end-start)
+	for _, loggingTime := range sched.loggingTimes[start:end] {
+		loggingTimes = append(loggingTimes, c.copyLoggingTime(scheduleId, loggingTime))
+	}
+	return loggingTimes, nil
+}
+
+// проверяет проект и вид работ по справочникам, если они заполнены
+func (c *Client) checkReferences(projectId int, workKindId int, httpMethod string, URN string) error {
+	if len(c.server.projects) > 0 {
+		found := false
+		for _, project := range c.server.projects {
+			found = found || project.Id == projectId
+		}
+		if !found {
+			return newError(http.StatusBadRequest, httpMethod, URN, "Project %d not found", projectId)
+		}
+	}
+	if len(c.server.workKinds) > 0 {
+		found := false
+		for _, workKind := range c.server.workKinds {
+			found = found || workKind.Id == workKindId
+		}
+		if !found {
+			return newError(http.StatusBadRequest, httpMethod, URN, "Work kind %d not found", workKindId)
+		}
+	}
+	return nil
+}
+
+func (c *Client) AddLoggingTime(scheduleId api.ScheduleId, loggingTime *api.AddLoggingTime) (*api.LoggingTime, error) {
+	return c.AddLoggingTimeContext(context.Background(), scheduleId, loggingTime)
+}
+
+func (c *Client) AddLoggingTimeContext(ctx context.Context, scheduleId api.ScheduleId, loggingTime *api.AddLoggingTime) (*api.LoggingTime, error) {
+	URN := fmt.Sprintf("%s/%d/%s", api.SchedulesURN, scheduleId, api.LoggingTimeURN)
+	me, err := c.lock(ctx, "AddLoggingTime", http.MethodPost, URN)
+	if err != nil {
+		return nil, err
+	}
+	defer c.unlock()
+	sched, err := c.ownSchedule(me, scheduleId, http.MethodPost, URN)
+	if err != nil {
+		return nil, err
+	}
+	if !sched.StatusCode.CanEdit() {
+		return nil, newError(http.StatusConflict, http.MethodPost, URN, "Schedule %d in status %s can not be changed", scheduleId, sched.StatusCode)
+	}
+	err = c.checkReferences(loggingTime.ProjectId, loggingTime.WorkKindId, http.MethodPost, URN)
+	if err != nil {
+		return nil, err
+	}
+	created := &api.LoggingTime{
+		CommentEmployee: loggingTime.CommentEmployee,
+		Day1Time:        loggingTime.Day1Time,
+		Day2Time:        loggingTime.Day2Time,
+		Day3Time:        loggingTime.Day3Time,
+		Day4Time:        loggingTime.Day4Time,
+		Day5Time:        loggingTime.Day5Time,
+		Day6Time:        loggingTime.Day6Time,
+		Day7Time:        loggingTime.Day7Time,
+		Id:              c.server.nextId(),
+		ProjectId:       loggingTime.ProjectId,
+		StatusCode:      api.Created,
+		Task:            loggingTime.Task,
+		WorkKindId:      loggingTime.WorkKindId,
+	}
+	sched.loggingTimes = append(sched.loggingTimes, created)
+	return c.copyLoggingTime(scheduleId, created), nil
+}
+
+func (c *Client) DetailLoggingTime(scheduleId api.ScheduleId, loggingTimeId api.LoggingTimeId) (*api.LoggingTime, error) {
+	return c.DetailLoggingTimeContext(context.Background(), scheduleId, loggingTimeId)
+}
+
+func (c *Client) DetailLoggingTimeContext(ctx context.Context, scheduleId api.ScheduleId, loggingTimeId api.LoggingTimeId) (*api.LoggingTime, error) {
+	URN := fmt.Sprintf("%s/%d/%s/%d", api.SchedulesURN, scheduleId, api.LoggingTimeURN, loggingTimeId)
+	me, err := c.lock(ctx, "DetailLoggingTime", http.MethodGet, URN)
+	if err != nil {
+		return nil, err
+	}
+	defer c.unlock()
+	sched, err := c.visibleSchedule(me, scheduleId, http.MethodGet, URN)
+	if err != nil {
+		return nil, err
+	}
+	_, loggingTime := findLoggingTime(sched, loggingTimeId)
+	if loggingTime == nil {
+		return nil, newError(http.StatusNotFound, http.MethodGet, URN, "Logging time %d not found", loggingTimeId)
+	}
+	return c.copyLoggingTime(scheduleId, loggingTime), nil
+}
+
+func (c *Client) UpdateLoggingTime(scheduleId api.ScheduleId, loggingTimeId api.LoggingTimeId, loggingTime *api.EditLoggingTime) (*api.LoggingTime, error) {
+	return c.UpdateLoggingTimeContext(context.Background(), scheduleId, loggingTimeId, loggingTime)
+}
+
+// UpdateLoggingTimeContext изменяет временную затрату. Если в изменениях передан
+// статус, изменение обрабатывается как утверждение или отклонение согласующим
+func (c *Client) UpdateLoggingTimeContext(ctx context.Context, scheduleId api.ScheduleId, loggingTimeId api.LoggingTimeId, loggingTime *api.EditLoggingTime) (*api.LoggingTime, error) {
+	URN := fmt.Sprintf("%s/%d/%s/%d", api.SchedulesURN, scheduleId, api.LoggingTimeURN, loggingTimeId)
+	me, err := c.lock(ctx, "UpdateLoggingTime", http.MethodPatch, URN)
+	if err != nil {
+		return nil, err
+	}
+	defer c.unlock()
+	if loggingTime.StatusCode != "" {
+		return c.decide(me, scheduleId, loggingTimeId, loggingTime.StatusCode, loggingTime.CommentAdminEmployee, URN)
+	}
+	sched, err := c.ownSchedule(me, scheduleId, http.MethodPatch, URN)
+	if err != nil {
+		return nil, err
+	}
+	_, current := findLoggingTime(sched, loggingTimeId)
+	if current == nil {
+		return nil, newError(http.StatusNotFound, http.MethodPatch, URN, "Logging time %d not found", loggingTimeId)
+	}
+	if !current.StatusCode.CanEdit() {
+		return nil, newError(http.StatusConflict, http.MethodPatch, URN, "Logging time %d in status %s can not be changed", loggingTimeId, current.StatusCode)
+	}
+	err = c.checkReferences(loggingTime.ProjectId, loggingTime.WorkKindId, http.MethodPatch, URN)
+	if err != nil {
+		return nil, err
+	}
+	current.CommentEmployee = loggingTime.CommentEmployee
+	current.Day1Time = loggingTime.Day1Time
+	current.Day2Time = loggingTime.Day2Time
+	current.Day3Time = loggingTime.Day3Time
+	current.Day4Time = loggingTime.Day4Time
+	current.Day5Time = loggingTime.Day5Time
+	current.Day6Time = loggingTime.Day6Time
+	current.Day7Time = loggingTime.Day7Time
+	current.ProjectId = loggingTime.ProjectId
+	current.Task = loggingTime.Task
+	current.WorkKindId = loggingTime.WorkKindId
+	return c.copyLoggingTime(scheduleId, current), nil
+}
+
+func (c *Client) DeleteLoggingTime(scheduleId api.ScheduleId, loggingTimeId api.LoggingTimeId) error {
+	return c.DeleteLoggingTimeContext(context.Background(), scheduleId, loggingTimeId)
+}
+
+func (c *Client) DeleteLoggingTimeContext(ctx context.Context, scheduleId api.ScheduleId, loggingTimeId api.LoggingTimeId) error {
+	URN := fmt.Sprintf("%s/%d/%s/%d", api.SchedulesURN, scheduleId, api.LoggingTimeURN, loggingTimeId)
+	me, err := c.lock(ctx, "DeleteLoggingTime", http.MethodDelete, URN)
+	if err != nil {
+		return err
+	}
+	defer c.unlock()
+	sched, err := c.ownSchedule(me, scheduleId, http.MethodDelete, URN)
+	if err != nil {
+		return err
+	}
+	i, loggingTime := findLoggingTime(sched, loggingTimeId)
+	if loggingTime == nil {
+		return newError(http.StatusNotFound, http.MethodDelete, URN, "Logging time %d not found", loggingTimeId)
+	}
+	if !loggingTime.StatusCode.CanEdit() {
+		return newError(http.StatusConflict, http.MethodDelete, URN, "Logging time %d in status %s can not be deleted", loggingTimeId, loggingTime.StatusCode)
+	}
+	sched.loggingTimes = append(sched.loggingTimes[:i], sched.loggingTimes[i+1:]...)
+	return nil
+}
+
+func (c *Client) SubmitForApproveSchedule(scheduleId api.ScheduleId) (*api.Schedule, error) {
+	return c.SubmitForApproveScheduleContext(context.Background(), scheduleId)
+}
+
+// SubmitForApproveScheduleContext отправляет расписание на утверждение вместе
+// с созданными и отклонёнными временными затратами
+func (c *Client) SubmitForApproveScheduleContext(ctx context.Context, scheduleId api.ScheduleId) (*api.Schedule, error) {
+	URN := fmt.Sprintf("%s/%d", api.SchedulesURN, scheduleId)
+	me, err := c.lock(ctx, "SubmitForApproveSchedule", http.MethodPatch, URN)
+	if err != nil {
+		return nil, err
+	}
+	defer c.unlock()
+	sched, err := c.ownSchedule(me, scheduleId, http.MethodPatch, URN)
+	if err != nil {
+		return nil, err
+	}
+	if !sched.StatusCode.CanSubmit() {
+		return nil, newError(http.StatusConflict, http.MethodPatch, URN, "Schedule %d in status %s can not be submitted", scheduleId, sched.StatusCode)
+	}
+	sched.StatusCode = api.ToApprove
+	for _, loggingTime := range sched.loggingTimes {
+		if loggingTime.StatusCode.CanEdit() {
+			loggingTime.StatusCode = api.ToApprove
+		}
+	}
+	return c.copySchedule(sched), nil
+}
+
+func (c *Client) ApproveLoggingTime(scheduleId api.ScheduleId, loggingTimeId api.LoggingTimeId, comment string) (*api.LoggingTime, error) {
+	return c.ApproveLoggingTimeContext(context.Background(), scheduleId, loggingTimeId, comment)
+}
+
+func (c *Client) ApproveLoggingTimeContext(ctx context.Context, scheduleId api.ScheduleId, loggingTimeId api.LoggingTimeId, comment string) (*api.LoggingTime, error) {
+	URN := fmt.Sprintf("%s/%d/%s/%d", api.SchedulesURN, scheduleId, api.LoggingTimeURN, loggingTimeId)
+	me, err := c.lock(ctx, "ApproveLoggingTime", http.MethodPatch, URN)
+	if err != nil {
+		return nil, err
+	}
+	defer c.unlock()
+	return c.decide(me, scheduleId, loggingTimeId, api.Approved, comment, URN)
+}
+
+func (c *Client) DeclineLoggingTime(scheduleId api.ScheduleId, loggingTimeId api.LoggingTimeId, comment string) (*api.LoggingTime, error) {
+	return c.DeclineLoggingTimeContext(context.Background(), scheduleId, loggingTimeId, comment)
+}
+
+func (c *Client) DeclineLoggingTimeContext(ctx context.Context, scheduleId api.ScheduleId, loggingTimeId api.LoggingTimeId, comment string) (*api.LoggingTime, error) {
+	URN := fmt.Sprintf("%s/%d/%s/%d", api.SchedulesURN, scheduleId, api.LoggingTimeURN, loggingTimeId)
+	me, err := c.lock(ctx, "DeclineLoggingTime", http.MethodPatch, URN)
+	if err != nil {
+		return nil, err
+	}
+	defer c.unlock()
+	return c.decide(me, scheduleId, loggingTimeId, api.Declined, comment, URN)
+}
+
+// утверждение или отклонение временной затраты согласующим. Расписание становится
+// отклонённым, если отклонена хотя бы одна затрата, и утверждённым, когда утверждены все
+func (c *Client) decide(me *employee, scheduleId api.ScheduleId, loggingTimeId api.LoggingTimeId, status api.StatusCode, comment string, URN string) (*api.LoggingTime, error) {
+	sched, err := c.visibleSchedule(me, scheduleId, http.MethodPatch, URN)
+	if err != nil {
+		return nil, err
+	}
+	if !c.approverOf(me, sched) {
+		return nil, newError(http.StatusForbidden, http.MethodPatch, URN, "Only the approver can approve or decline logging times of schedule %d", scheduleId)
+	}
+	_, loggingTime := findLoggingTime(sched, loggingTimeId)
+	if loggingTime == nil {
+		return nil, newError(http.StatusNotFound, http.MethodPatch, URN, "Logging time %d not found", loggingTimeId)
+	}
+	if status != api.Approved && status != api.Declined || !loggingTime.StatusCode.CanTransition(status) {
+		return nil, newError(http.StatusConflict, http.MethodPatch, URN, "Logging time %d can not change status from %s to %s", loggingTimeId, loggingTime.StatusCode, status)
+	}
+	loggingTime.StatusCode = status
+	loggingTime.AdminEmployee = me.Employee
+	loggingTime.CommentAdminEmployee = comment
+
+	approved := true
+	for _, other := range sched.loggingTimes {
+		if other.StatusCode == api.Declined {
+			sched.StatusCode = api.Declined
+			return c.copyLoggingTime(scheduleId, loggingTime), nil
+		}
+		approved = approved && other.StatusCode == api.Approved
+	}
+	if approved {
+		sched.StatusCode = api.Approved
+	}
+	return c.copyLoggingTime(scheduleId, loggingTime), nil
+}
+
+func matchesSearch(search string, values ...string) bool {
+	search = strings.ToLower(search)
+	for _, value := range values {
+		if strings.Contains(strings.ToLower(value), search) {
+			return true
+		}
+	}
+	return search == ""
+}
+
+func (c *Client) Projects(options *api.OptionsP) ([]*api.Project, error) {
+	return c.ProjectsContext(context.Background(), options)
+}
+
+func (c *Client) ProjectsContext(ctx context.Context, options *api.OptionsP) ([]*api.Project, error) {
+	_, err := c.lock(ctx, "Projects", http.MethodGet, api.ProjectsURN)
+	if err != nil {
+		return nil, err
+	}
+	defer c.unlock()
+	opts := api.OptionsP{}
+	if options != nil {
+		opts = *options
+	}
+	projects := []*api.Project{}
+	for _, project := range c.server.projects {
+		if matchesSearch(opts.Search, project.Code, project.Name) {
+			projectCopy := project
+			projects = append(projects, &projectCopy)
+		}
+	}
+	start, end := pageBounds(len(projects), opts.Page, opts.Size)
+	return projects[start:end], nil
+}
+
+func (c *Client) WorkKinds(options *api.OptionsWK) ([]*api.WorkKind, error) {
+	return c.WorkKindsContext(context.Background(), options)
+}
+
+func (c *Client) WorkKindsContext(ctx context.Context, options *api.OptionsWK) ([]*api.WorkKind, error) {
+	_, err := c.lock(ctx, "WorkKinds", http.MethodGet, api.WorkKindsURN)
+	if err != nil {
+		return nil, err
+	}
+	defer c.unlock()
+	search := ""
+	if options != nil {
+		search = options.Search
+	}
+	workKinds := []*api.WorkKind{}
+	for _, workKind := range c.server.workKinds {
+		if matchesSearch(search, workKind.Code, workKind.Name) {
+			workKindCopy := workKind
+			workKinds = append(workKinds, &workKindCopy)
+		}
+	}
+	return workKinds, nil
+}
+
+func (c *Client) Periods(options *api.OptionsPD) ([]*api.Period, error) {
+	return c.PeriodsContext(context.Background(), options)
+}
+
+// PeriodsContext возвращает периоды, пересекающиеся с интервалом дат из опций
+func (c *Client) PeriodsContext(ctx context.Context, options *api.OptionsPD) ([]*api.Period, error) {
+	_, err := c.lock(ctx, "Periods", http.MethodGet, api.PeriodsURN)
+	if err != nil {
+		return nil, err
+	}
+	defer c.unlock()
+	opts := api.OptionsPD{}
+	if options != nil {
+		opts = *options
+	}
+	periods := []*api.Period{}
+	for _, period := range c.server.periods {
+		if !opts.EndDate.IsZero() && period.StartDate.After(api.DateOf(opts.EndDate).Time) {
+			continue
+		}
+		if !opts.StartDate.IsZero() && period.EndDate.Before(api.DateOf(opts.StartDate).Time) {
+			continue
+		}
+		periodCopy := period
+		periods = append(periods, &periodCopy)
+	}
+	start, end := pageBounds(len(periods), opts.Page, opts.Size)
+	return periods[start:end], nil
+}
@@ -0,0 +1,288 @@
+// Package suftfake - хранящая состояние в памяти реализация api.API для тестов
+// без сети. Server моделирует сотрудников, справочники, расписания и временные
+// затраты с переходами статусов: создано -> на утверждении -> утверждено/отклонено.
+// Client работает от имени одного сотрудника, клиенты одного Server видят общее
+// состояние, поэтому сотрудник и его согласующий могут пройти весь процесс.
+//
+//	server := suftfake.NewServer()
+//	boss := server.AddEmployee(api.Employee{Email: "boss@example.com"}, 0)
+//	user := server.AddEmployee(api.Employee{Email: "user@example.com"}, boss.Id)
+//	periods := server.AddWeeks(time.Date(2021, 2, 15, 0, 0, 0, 0, time.UTC), 4)
+//	client := server.Client(user.Id)
+//	schedule, err := client.AddSchedule(api.PeriodId(periods[0].Id))
+//
+// Объекты, которые возвращает Client, связаны с ним, поэтому их методы
+// (например Schedule.AddLoggingTime) работают так же, как с api.Client.
+package suftfake
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"suftsdk/pkg/api"
+)
+
+// размер страницы по умолчанию, как у сервера СУФТ
+const defaultPageSize int = 5
+
+// вызов метода Client, передаётся в Hook
+type Call struct {
+	// id сотрудника, от имени которого работает клиент
+	EmployeeId int
+	// имя метода api.API без суффикса Context, например "AddLoggingTime"
+	Method string
+}
+
+// Hook вызывается перед каждым вызовом метода Client. Ошибка, которую вернул Hook,
+// возвращается из метода без изменения состояния
+type Hook func(ctx context.Context, call Call) error
+
+type employee struct {
+	api.Employee
+	approverId int
+}
+
+type schedule struct {
+	api.Schedule
+	loggingTimes []*api.LoggingTime
+}
+
+// Server хранит общее для всех клиентов состояние. Методы можно вызывать
+// из нескольких горутин одновременно
+type Server struct {
+	mu        sync.Mutex
+	employees map[int]*employee
+	projects  []api.Project
+	workKinds []api.WorkKind
+	periods   []api.Period
+	schedules map[api.ScheduleId]*schedule
+	lastId    int
+	latency   time.Duration
+	hook      Hook
+	failures  map[string][]error
+	calls     map[string]int
+}
+
+func NewServer() *Server {
+	return &Server{
+		employees: map[int]*employee{},
+		schedules: map[api.ScheduleId]*schedule{},
+		failures:  map[string][]error{},
+		calls:     map[string]int{},
+	}
+}
+
+// id для новых объектов, общий счётчик для всех типов
+func (s *Server) nextId() int {
+	s.lastId++
+	return s.lastId
+}
+
+// AddEmployee добавляет сотрудника. approverId - id согласующего, который видит
+// расписания сотрудника с ролью api.Approver и утверждает его затраты; 0 - без согласующего.
+// Если Id сотрудника не задан, он назначается автоматически
+func (s *Server) AddEmployee(e api.Employee, approverId int) api.Employee {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e.Id == 0 {
+		e.Id = s.nextId()
+	}
+	s.employees[e.Id] = &employee{Employee: e, approverId: approverId}
+	return e
+}
+
+// AddProject добавляет проект в справочник. Если справочник проектов пуст,
+// id проекта во временных затратах не проверяется
+func (s *Server) AddProject(project api.Project) api.Project {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if project.Id == 0 {
+		project.Id = s.nextId()
+	}
+	s.projects = append(s.projects, project)
+	return project
+}
+
+// AddWorkKind добавляет вид работ в справочник. Если справочник видов работ пуст,
+// id вида работ во временных затратах не проверяется
+func (s *Server) AddWorkKind(workKind api.WorkKind) api.WorkKind {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if workKind.Id == 0 {
+		workKind.Id = s.nextId()
+	}
+	s.workKinds = append(s.workKinds, workKind)
+	return workKind
+}
+
+// AddPeriod добавляет период
+func (s *Server) AddPeriod(period api.Period) api.Period {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if period.Id == 0 {
+		period.Id = s.nextId()
+	}
+	s.periods = append(s.periods, period)
+	sort.Slice(s.periods, func(i, j int) bool {
+		return s.periods[i].StartDate.Before(s.periods[j].StartDate.Time)
+	})
+	return period
+}
+
+// AddWeeks добавляет count недельных периодов, начиная с недели, в которую попадает from
+func (s *Server) AddWeeks(from time.Time, count int) []api.Period {
+	monday := api.DateOf(from)
+	monday = monday.AddDays(-(int(monday.Weekday()) + 6) % 7)
+	periods := make([]api.Period, 0, count)
+	for i := 0; i < count; i++ {
+		start := monday.AddDays(7 * i)
+		_, week := start.ISOWeek()
+		periods = append(periods, s.AddPeriod(api.Period{
+			StartDate:  start,
+			EndDate:    start.AddDays(6),
+			CloseDate:  start.AddDays(7),
+			WeekNumber: week,
+		}))
+	}
+	return periods
+}
+
+// Client возвращает клиент, работающий от имени сотрудника. Для неизвестного
+// сотрудника методы клиента возвращают ошибку, сравнимую с api.ErrUnauthorized
+func (s *Server) Client(employeeId int) *Client {
+	return &Client{server: s, employeeId: employeeId}
+}
+
+// Schedule возвращает копию расписания для проверок в тестах
+func (s *Server) Schedule(scheduleId api.ScheduleId) (api.Schedule, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sched, ok := s.schedules[scheduleId]
+	if !ok {
+		return api.Schedule{}, false
+	}
+	return sched.Schedule, true
+}
+
+// LoggingTimes возвращает копии временных затрат расписания для проверок в тестах
+func (s *Server) LoggingTimes(scheduleId api.ScheduleId) []api.LoggingTime {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sched, ok := s.schedules[scheduleId]
+	if !ok {
+		return nil
+	}
+	loggingTimes := make([]api.LoggingTime, len(sched.loggingTimes))
+	for i, loggingTime := range sched.loggingTimes {
+		loggingTimes[i] = *loggingTime
+	}
+	return loggingTimes
+}
+
+// SetLatency задаёт задержку перед каждым вызовом метода клиента.
+// Задержка прерывается отменой контекста
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = latency
+}
+
+// SetHook задаёт функцию, вызываемую перед каждым вызовом метода клиента; nil - без функции
+func (s *Server) SetHook(hook Hook) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hook = hook
+}
+
+// FailNext добавляет ошибку, которую вернёт следующий вызов метода method
+// (имя метода api.API без суффикса Context). Ошибки одного метода возвращаются
+// по очереди, по одной на вызов. Для ошибок сервера используйте Error
+func (s *Server) FailNext(method string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[method] = append(s.failures[method], err)
+}
+
+// Calls возвращает количество вызовов метода method, включая завершившиеся ошибкой
+func (s *Server) Calls(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method]
+}
+
+// Error возвращает ошибку, которую вернул бы api.Client при ответе сервера
+// со статусом statusCode, например Error(http.StatusForbidden, "Access denied")
+func Error(statusCode int, message string) *api.APIError {
+	return newError(statusCode, "", "", message)
+}
+
+func newError(statusCode int, method string, URN string, format string, args ...interface{}) *api.APIError {
+	message := fmt.Sprintf(format, args...)
+	return &api.APIError{
+		StatusCode: statusCode,
+		Method:     method,
+		URN:        URN,
+		Body: &api.ErrorBody{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Status:    statusCode,
+			Message:   message,
+			Path:      "/" + URN,
+		},
+		RawBody: []byte(message),
+	}
+}
+
+// общая часть каждого вызова: учёт, задержка, Hook и ошибки из FailNext
+func (s *Server) begin(ctx context.Context, employeeId int, method string) error {
+	s.mu.Lock()
+	s.calls[method]++
+	latency := s.latency
+	hook := s.hook
+	s.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	if hook != nil {
+		if err := hook(ctx, Call{EmployeeId: employeeId, Method: method}); err != nil {
+			return err
+		}
+	}
+	// ошибка снимается с очереди, только когда вызов её возвращает
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	failures := s.failures[method]
+	if len(failures) == 0 {
+		return nil
+	}
+	s.failures[method] = failures[1:]
+	return failures[0]
+}
+
+// страница списка, page начинается с 0
+func pageBounds(length int, page int, size int) (int, int) {
+	if size <= 0 {
+		size = defaultPageSize
+	}
+	start := page * size
+	if page < 0 || start > length {
+		start = length
+	}
+	end := start + size
+	if end > length {
+		end = length
+	}
+	return start, end
+}
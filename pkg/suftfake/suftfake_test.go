@@ -0,0 +1,264 @@
+package suftfake
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"suftsdk/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixture struct {
+	server   *Server
+	boss     *Client
+	user     *Client
+	periods  []api.Period
+	project  api.Project
+	workKind api.WorkKind
+}
+
+func newFixture() *fixture {
+	server := NewServer()
+	boss := server.AddEmployee(api.Employee{Email: "boss@example.com", LastName: "Петров"}, 0)
+	user := server.AddEmployee(api.Employee{Email: "user@example.com", LastName: "Иванов"}, boss.Id)
+	return &fixture{
+		server:   server,
+		boss:     server.Client(boss.Id),
+		user:     server.Client(user.Id),
+		periods:  server.AddWeeks(time.Date(2021, 2, 17, 0, 0, 0, 0, time.UTC), 3),
+		project:  server.AddProject(api.Project{Code: "SUFT", Name: "Система учёта"}),
+		workKind: server.AddWorkKind(api.WorkKind{Code: "DEV", Name: "Разработка"}),
+	}
+}
+
+func (f *fixture) addLoggingTime(t *testing.T, scheduleId api.ScheduleId, task string) *api.LoggingTime {
+	loggingTime, err := f.user.AddLoggingTime(scheduleId, &api.AddLoggingTime{
+		Day1Time: 8, ProjectId: f.project.Id, Task: task, WorkKindId: f.workKind.Id,
+	})
+	require.NoError(t, err)
+	return loggingTime
+}
+
+func TestAddWeeks(t *testing.T) {
+	f := newFixture()
+	require.Len(t, f.periods, 3)
+	assert.Equal(t, api.NewDate(2021, 2, 15), f.periods[0].StartDate)
+	assert.Equal(t, api.NewDate(2021, 2, 21), f.periods[0].EndDate)
+	assert.Equal(t, 7, f.periods[0].WeekNumber)
+	assert.Equal(t, api.NewDate(2021, 3, 1), f.periods[2].StartDate)
+}
+
+func TestWorkflow(t *testing.T) {
+	f := newFixture()
+	schedule, err := f.user.AddSchedule(api.PeriodId(f.periods[0].Id))
+	require.NoError(t, err)
+	assert.Equal(t, api.Created, schedule.StatusCode)
+	assert.Equal(t, "user@example.com", schedule.Author.Email)
+	scheduleId := api.ScheduleId(schedule.Id)
+
+	first := f.addLoggingTime(t, scheduleId, "task 1")
+	second := f.addLoggingTime(t, scheduleId, "task 2")
+
+	submitted, err := f.user.SubmitForApproveSchedule(scheduleId)
+	require.NoError(t, err)
+	assert.Equal(t, api.ToApprove, submitted.StatusCode)
+	_, err = f.user.AddLoggingTime(scheduleId, &api.AddLoggingTime{ProjectId: f.project.Id, WorkKindId: f.workKind.Id})
+	assert.ErrorIs(t, err, api.ErrConflict)
+
+	schedules, err := f.boss.Schedules(&api.OptionsS{CreatorApprover: api.Approver})
+	require.NoError(t, err)
+	require.Len(t, schedules, 1)
+	assert.Equal(t, schedule.Id, schedules[0].Id)
+
+	approved, err := f.boss.ApproveLoggingTime(scheduleId, api.LoggingTimeId(first.Id), "ok")
+	require.NoError(t, err)
+	assert.Equal(t, api.Approved, approved.StatusCode)
+	assert.Equal(t, "boss@example.com", approved.AdminEmployee.Email)
+	declined, err := f.boss.DeclineLoggingTime(scheduleId, api.LoggingTimeId(second.Id), "too much")
+	require.NoError(t, err)
+	assert.Equal(t, api.Declined, declined.StatusCode)
+	current, _ := f.server.Schedule(scheduleId)
+	assert.Equal(t, api.Declined, current.StatusCode)
+
+	edit := api.NewEditLoggingTime(declined)
+	edit.Day1Time = 4
+	updated, err := f.user.UpdateLoggingTime(scheduleId, api.LoggingTimeId(second.Id), edit)
+	require.NoError(t, err)
+	assert.Equal(t, 4.0, updated.Day1Time)
+	_, err = f.user.UpdateLoggingTime(scheduleId, api.LoggingTimeId(first.Id), api.NewEditLoggingTime(approved))
+	assert.ErrorIs(t, err, api.ErrConflict)
+
+	_, err = f.user.SubmitForApproveSchedule(scheduleId)
+	require.NoError(t, err)
+	_, err = f.boss.ApproveLoggingTime(scheduleId, api.LoggingTimeId(second.Id), "")
+	require.NoError(t, err)
+	current, _ = f.server.Schedule(scheduleId)
+	assert.Equal(t, api.Approved, current.StatusCode)
+	for _, loggingTime := range f.server.LoggingTimes(scheduleId) {
+		assert.Equal(t, api.Approved, loggingTime.StatusCode)
+	}
+}
+
+func TestRoles(t *testing.T) {
+	f := newFixture()
+	other := f.server.Client(f.server.AddEmployee(api.Employee{Email: "other@example.com"}, 0).Id)
+	schedule, err := f.user.AddSchedule(api.PeriodId(f.periods[0].Id))
+	require.NoError(t, err)
+	scheduleId := api.ScheduleId(schedule.Id)
+	loggingTime := f.addLoggingTime(t, scheduleId, "task")
+
+	_, err = other.DetailSchedule(scheduleId)
+	assert.ErrorIs(t, err, api.ErrForbidden)
+	_, err = f.boss.AddLoggingTime(scheduleId, &api.AddLoggingTime{})
+	assert.ErrorIs(t, err, api.ErrForbidden)
+	_, err = f.user.SubmitForApproveSchedule(scheduleId)
+	require.NoError(t, err)
+	_, err = f.user.ApproveLoggingTime(scheduleId, api.LoggingTimeId(loggingTime.Id), "")
+	assert.ErrorIs(t, err, api.ErrForbidden)
+	_, err = f.boss.DetailLoggingTime(scheduleId, api.LoggingTimeId(loggingTime.Id))
+	assert.NoError(t, err)
+
+	_, err = f.user.AddSchedule(api.PeriodId(f.periods[0].Id))
+	assert.ErrorIs(t, err, api.ErrConflict)
+	_, err = f.user.AddSchedule(999)
+	assert.ErrorIs(t, err, api.ErrNotFound)
+	_, err = f.server.Client(999).Schedules(nil)
+	assert.ErrorIs(t, err, api.ErrUnauthorized)
+}
+
+func TestReferences(t *testing.T) {
+	f := newFixture()
+	schedule, err := f.user.AddSchedule(api.PeriodId(f.periods[0].Id))
+	require.NoError(t, err)
+	_, err = f.user.AddLoggingTime(api.ScheduleId(schedule.Id), &api.AddLoggingTime{ProjectId: 12345, WorkKindId: f.workKind.Id})
+	apiErr := &api.APIError{}
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, "Project 12345 not found", apiErr.Message())
+
+	projects, err := f.user.Projects(&api.OptionsP{Search: "учёт"})
+	require.NoError(t, err)
+	assert.Equal(t, []*api.Project{&f.project}, projects)
+	f.server.AddProject(api.Project{Code: "HR", Name: "Кадры"})
+	other := f.server.AddProject(api.Project{Code: "OTHER", Name: "Другой учёт"})
+	projects, err = f.user.Projects(&api.OptionsP{Search: "учёт", Page: 1, Size: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []*api.Project{&other}, projects)
+	workKinds, err := f.user.WorkKinds(&api.OptionsWK{Search: "нет"})
+	require.NoError(t, err)
+	assert.Empty(t, workKinds)
+
+	period, err := api.PeriodForDate(f.user, time.Date(2021, 2, 24, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, f.periods[1].Id, period.Id)
+}
+
+func TestHelpersWorkWithFake(t *testing.T) {
+	f := newFixture()
+	for _, period := range f.periods {
+		_, err := f.user.AddSchedule(api.PeriodId(period.Id))
+		require.NoError(t, err)
+	}
+	schedules, err := api.AllSchedules(f.user, &api.OptionsS{Size: 2})
+	require.NoError(t, err)
+	assert.Len(t, schedules, 3)
+
+	source, err := api.ScheduleForPeriod(f.user, api.PeriodId(f.periods[0].Id))
+	require.NoError(t, err)
+	f.addLoggingTime(t, api.ScheduleId(source.Id), "task")
+	result, err := api.CopyWeek(f.user, api.ScheduleId(source.Id), api.PeriodId(f.periods[1].Id), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Failed())
+	assert.Len(t, f.server.LoggingTimes(api.ScheduleId(result.Schedule.Id)), 1)
+}
+
+func TestObjectMethods(t *testing.T) {
+	f := newFixture()
+	schedule, err := f.user.AddSchedule(api.PeriodId(f.periods[0].Id))
+	require.NoError(t, err)
+	loggingTime, err := schedule.AddLoggingTime(&api.AddLoggingTime{
+		Day1Time: 8, ProjectId: f.project.Id, Task: "task", WorkKindId: f.workKind.Id,
+	})
+	require.NoError(t, err)
+	updated, err := loggingTime.Update(&api.EditLoggingTime{
+		Day1Time: 6, ProjectId: f.project.Id, Task: "task", WorkKindId: f.workKind.Id,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 6.0, updated.Day1Time)
+
+	submitted, err := schedule.SubmitForApproveSchedule()
+	require.NoError(t, err)
+	assert.Equal(t, api.ToApprove, submitted.StatusCode)
+	require.NoError(t, schedule.Refresh())
+	assert.Equal(t, api.ToApprove, schedule.StatusCode)
+
+	loggingTimes, err := f.boss.LoggingTimeList(api.ScheduleId(schedule.Id), nil)
+	require.NoError(t, err)
+	require.Len(t, loggingTimes, 1)
+	approved, err := loggingTimes[0].ApproveLoggingTime("ok")
+	require.NoError(t, err)
+	assert.Equal(t, api.Approved, approved.StatusCode)
+	summary, err := submitted.Summary(8)
+	require.NoError(t, err)
+	assert.Equal(t, 6.0, summary.Total)
+}
+
+func TestFailNextAndHook(t *testing.T) {
+	f := newFixture()
+	f.server.FailNext("Schedules", Error(http.StatusInternalServerError, "boom"))
+	_, err := f.user.Schedules(nil)
+	apiErr := &api.APIError{}
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusInternalServerError, apiErr.StatusCode)
+	_, err = f.user.Schedules(nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, f.server.Calls("Schedules"))
+
+	hookErr := errors.New("hook")
+	f.server.SetHook(func(ctx context.Context, call Call) error {
+		if call.Method == "AddSchedule" && call.EmployeeId == f.user.EmployeeId() {
+			return hookErr
+		}
+		return nil
+	})
+	_, err = f.user.AddSchedule(api.PeriodId(f.periods[0].Id))
+	assert.ErrorIs(t, err, hookErr)
+	schedules, err := f.user.Schedules(nil)
+	require.NoError(t, err)
+	assert.Empty(t, schedules)
+}
+
+func TestFailNextKeptOnEarlyReturn(t *testing.T) {
+	f := newFixture()
+	failure := Error(http.StatusServiceUnavailable, "unavailable")
+	f.server.FailNext("Schedules", failure)
+	hookErr := errors.New("hook")
+	f.server.SetHook(func(ctx context.Context, call Call) error { return hookErr })
+	_, err := f.user.Schedules(nil)
+	require.ErrorIs(t, err, hookErr)
+
+	f.server.SetHook(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = f.user.SchedulesContext(ctx, nil)
+	require.ErrorIs(t, err, context.Canceled)
+
+	_, err = f.user.Schedules(nil)
+	assert.Equal(t, failure, err)
+	_, err = f.user.Schedules(nil)
+	require.NoError(t, err)
+}
+
+func TestLatency(t *testing.T) {
+	f := newFixture()
+	f.server.SetLatency(time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := f.user.SchedulesContext(ctx, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,334 @@
+package sufttest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"suftsdk/internal/auth"
+	"suftsdk/pkg/api"
+	"suftsdk/pkg/suftfake"
+)
+
+const (
+	accessTokenCookie  = "Access-token"
+	refreshTokenCookie = "Refresh-token"
+	dateLayout         = "2006-01-02"
+)
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, BasePath) {
+		writeError(w, r, http.StatusNotFound, "No route for %s", r.URL.Path)
+		return
+	}
+	route := strings.TrimPrefix(r.URL.Path, BasePath)
+	switch route {
+	case auth.AuthURL:
+		s.authenticate(w, r)
+		return
+	case auth.RefreshURL:
+		s.refresh(w, r)
+		return
+	}
+
+	cookie, err := r.Cookie(accessTokenCookie)
+	if err != nil || cookie.Value == "" {
+		writeError(w, r, http.StatusUnauthorized, "Access token is missing")
+		return
+	}
+	employeeId, ok := s.employeeByAccessToken(cookie.Value)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, "Access token is invalid or expired")
+		return
+	}
+	serveAPI(w, r, route, s.fake.Client(employeeId))
+}
+
+func (s *Server) authenticate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "Method %s not allowed", r.Method)
+		return
+	}
+	if r.Header.Get("Auth-method") != "Password" {
+		writeError(w, r, http.StatusBadRequest, "Unsupported auth method %q", r.Header.Get("Auth-method"))
+		return
+	}
+	credentials := struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&credentials); err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid request body: %v", err)
+		return
+	}
+
+	s.mu.Lock()
+	u, ok := s.users[credentials.Username]
+	if !ok || u.password != credentials.Password {
+		s.mu.Unlock()
+		writeError(w, r, http.StatusUnauthorized, "Bad credentials")
+		return
+	}
+	token := s.issue(u.employeeId)
+	s.mu.Unlock()
+
+	s.setTokenCookies(w, token)
+	w.WriteHeader(http.StatusOK)
+}
+
+// обновляет пару токенов, использованный refresh-токен становится недействительным
+func (s *Server) refresh(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		writeError(w, r, http.StatusMethodNotAllowed, "Method %s not allowed", r.Method)
+		return
+	}
+	cookie, err := r.Cookie(refreshTokenCookie)
+	if err != nil || cookie.Value == "" {
+		writeError(w, r, http.StatusUnauthorized, "Refresh token is missing")
+		return
+	}
+
+	s.mu.Lock()
+	t, ok := s.refreshTokens[cookie.Value]
+	if !ok || !s.now().Before(t.expires) {
+		delete(s.refreshTokens, cookie.Value)
+		s.mu.Unlock()
+		writeError(w, r, http.StatusUnauthorized, "Refresh token is invalid or expired")
+		return
+	}
+	delete(s.refreshTokens, cookie.Value)
+	token := s.issue(t.employeeId)
+	s.mu.Unlock()
+
+	s.setTokenCookies(w, token)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) setTokenCookies(w http.ResponseWriter, token *auth.Token) {
+	s.mu.Lock()
+	now := s.now()
+	s.mu.Unlock()
+	http.SetCookie(w, &http.Cookie{
+		Name:     accessTokenCookie,
+		Value:    token.AccessToken,
+		Path:     "/",
+		Expires:  now.Add(s.accessTokenTTL),
+		HttpOnly: true,
+	})
+	http.SetCookie(w, &http.Cookie{
+		Name:     refreshTokenCookie,
+		Value:    token.RefreshToken,
+		Path:     "/",
+		Expires:  now.Add(s.refreshTokenTTL),
+		HttpOnly: true,
+	})
+}
+
+// маршруты api/v1, вызовы выполняются от имени сотрудника клиента
+func serveAPI(w http.ResponseWriter, r *http.Request, route string, client *suftfake.Client) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	page, size, err := pageParams(query)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	switch {
+	case route == api.ProjectsURN && r.Method == http.MethodGet:
+		projects, err := client.ProjectsContext(ctx, &api.OptionsP{Page: page, Size: size, Search: query.Get("search")})
+		writeResult(w, r, http.StatusOK, projects, err)
+		return
+	case route == api.WorkKindsURN && r.Method == http.MethodGet:
+		workKinds, err := client.WorkKindsContext(ctx, &api.OptionsWK{Search: query.Get("search")})
+		writeResult(w, r, http.StatusOK, workKinds, err)
+		return
+	case route == api.PeriodsURN && r.Method == http.MethodGet:
+		options := &api.OptionsPD{Page: page, Size: size}
+		if options.StartDate, err = dateParam(query, "startDate"); err == nil {
+			options.EndDate, err = dateParam(query, "endDate")
+		}
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "%v", err)
+			return
+		}
+		periods, err := client.PeriodsContext(ctx, options)
+		writeResult(w, r, http.StatusOK, periods, err)
+		return
+	case route != api.SchedulesURN && !strings.HasPrefix(route, api.SchedulesURN+"/"):
+		writeError(w, r, http.StatusNotFound, "No route for %s", r.URL.Path)
+		return
+	}
+
+	// api/v1/schedules[/{id}[/logging-times[/{id}]]]
+	parts := strings.Split(strings.TrimPrefix(route, api.SchedulesURN), "/")[1:]
+	var scheduleId api.ScheduleId
+	var loggingTimeId api.LoggingTimeId
+	if len(parts) > 0 {
+		id, err := strconv.Atoi(parts[0])
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "Invalid schedule id %q", parts[0])
+			return
+		}
+		scheduleId = api.ScheduleId(id)
+	}
+	if len(parts) > 1 && parts[1] != api.LoggingTimeURN || len(parts) > 3 {
+		writeError(w, r, http.StatusNotFound, "No route for %s", r.URL.Path)
+		return
+	}
+	if len(parts) > 2 {
+		id, err := strconv.Atoi(parts[2])
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "Invalid logging time id %q", parts[2])
+			return
+		}
+		loggingTimeId = api.LoggingTimeId(id)
+	}
+
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodGet:
+		options := &api.OptionsS{Page: page, Size: size, CreatorApprover: api.Role(query.Get("creatorApprover"))}
+		schedules, err := client.SchedulesContext(ctx, options)
+		writeResult(w, r, http.StatusOK, schedules, err)
+	case len(parts) == 0 && r.Method == http.MethodPost:
+		body := struct {
+			PeriodId api.PeriodId `json:"periodId"`
+		}{}
+		if !decodeBody(w, r, &body) {
+			return
+		}
+		schedule, err := client.AddScheduleContext(ctx, body.PeriodId)
+		writeResult(w, r, http.StatusCreated, schedule, err)
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		schedule, err := client.DetailScheduleContext(ctx, scheduleId)
+		writeResult(w, r, http.StatusOK, schedule, err)
+	case len(parts) == 1 && r.Method == http.MethodPatch:
+		body := struct {
+			StatusCode api.StatusCode `json:"statusCode"`
+		}{}
+		if !decodeBody(w, r, &body) {
+			return
+		}
+		if body.StatusCode != api.ToApprove {
+			writeError(w, r, http.StatusBadRequest, "Unsupported status %q", body.StatusCode)
+			return
+		}
+		schedule, err := client.SubmitForApproveScheduleContext(ctx, scheduleId)
+		writeResult(w, r, http.StatusOK, schedule, err)
+	case len(parts) == 2 && r.Method == http.MethodGet:
+		loggingTimes, err := client.LoggingTimeListContext(ctx, scheduleId, &api.OptionsLT{Page: page, Size: size})
+		writeResult(w, r, http.StatusOK, loggingTimes, err)
+	case len(parts) == 2 && r.Method == http.MethodPost:
+		loggingTime := &api.AddLoggingTime{}
+		if !decodeBody(w, r, loggingTime) {
+			return
+		}
+		created, err := client.AddLoggingTimeContext(ctx, scheduleId, loggingTime)
+		writeResult(w, r, http.StatusCreated, created, err)
+	case len(parts) == 3 && r.Method == http.MethodGet:
+		loggingTime, err := client.DetailLoggingTimeContext(ctx, scheduleId, loggingTimeId)
+		writeResult(w, r, http.StatusOK, loggingTime, err)
+	case len(parts) == 3 && r.Method == http.MethodPatch:
+		loggingTime := &api.EditLoggingTime{}
+		if !decodeBody(w, r, loggingTime) {
+			return
+		}
+		updated, err := client.UpdateLoggingTimeContext(ctx, scheduleId, loggingTimeId, loggingTime)
+		writeResult(w, r, http.StatusOK, updated, err)
+	case len(parts) == 3 && r.Method == http.MethodDelete:
+		err := client.DeleteLoggingTimeContext(ctx, scheduleId, loggingTimeId)
+		writeResult(w, r, http.StatusOK, nil, err)
+	default:
+		writeError(w, r, http.StatusMethodNotAllowed, "Method %s not allowed", r.Method)
+	}
+}
+
+func pageParams(query url.Values) (int, int, error) {
+	page, err := intParam(query, "page")
+	if err != nil {
+		return 0, 0, err
+	}
+	size, err := intParam(query, "size")
+	if err != nil {
+		return 0, 0, err
+	}
+	return page, size, nil
+}
+
+func intParam(query url.Values, name string) (int, error) {
+	value := query.Get(name)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid parameter %s=%q", name, value)
+	}
+	return n, nil
+}
+
+func dateParam(query url.Values, name string) (time.Time, error) {
+	value := query.Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid parameter %s=%q", name, value)
+	}
+	return date, nil
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid request body: %v", err)
+		return false
+	}
+	return true
+}
+
+// пишет результат вызова suftfake.Client: ошибку api.APIError с её статусом,
+// другие ошибки - со статусом 500, иначе v в формате JSON
+func writeResult(w http.ResponseWriter, r *http.Request, statusCode int, v interface{}, err error) {
+	if err != nil {
+		apiErr := &api.APIError{}
+		if errors.As(err, &apiErr) {
+			writeError(w, r, apiErr.StatusCode, "%s", apiErr.Message())
+			return
+		}
+		writeError(w, r, http.StatusInternalServerError, "%v", err)
+		return
+	}
+	if v == nil {
+		w.WriteHeader(statusCode)
+		return
+	}
+	writeJSON(w, statusCode, v)
+}
+
+// ошибка в формате сервера СУФТ, см. api.ErrorBody
+func writeError(w http.ResponseWriter, r *http.Request, statusCode int, format string, args ...interface{}) {
+	writeJSON(w, statusCode, &api.ErrorBody{
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Status:    statusCode,
+		Error:     http.StatusText(statusCode),
+		Message:   fmt.Sprintf(format, args...),
+		Path:      r.URL.Path,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(body)
+}
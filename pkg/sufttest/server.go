@@ -0,0 +1,173 @@
+// Package sufttest - HTTP-сервер на httptest, реализующий маршруты СУФТ v1,
+// которые использует SDK: вход и обновление токенов (security/authenticate,
+// security/refresh-token), расписания, временные затраты и справочники.
+// Токены выдаются в cookie Access-token и Refresh-token и имеют срок жизни,
+// запрос с истёкшим или неизвестным токеном получает ответ 401. Данные хранит
+// suftfake.Server, поэтому правила доступа и статусов совпадают с suftfake.
+//
+//	server := sufttest.NewServer(nil)
+//	defer server.Close()
+//	boss := server.AddUser("boss@example.com", "secret", 0)
+//	server.AddUser("user@example.com", "secret", boss.Id)
+//	server.Fake().AddWeeks(time.Now(), 4)
+//	client, err := api.NewClient("user@example.com", "secret", &api.OptionsNC{SuftAPIURL: server.URL()})
+package sufttest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"suftsdk/internal/auth"
+	"suftsdk/pkg/api"
+	"suftsdk/pkg/suftfake"
+)
+
+// путь API на сервере, как у https://dev.gnivc.ru/tools/suft/api/v1/
+const BasePath = "/tools/suft/api/v1/"
+
+const (
+	defaultAccessTokenTTL  = 15 * time.Minute
+	defaultRefreshTokenTTL = 24 * time.Hour
+)
+
+type Options struct {
+	// время жизни access-токена, по умолчанию 15 минут
+	AccessTokenTTL time.Duration
+	// время жизни refresh-токена, по умолчанию 24 часа
+	RefreshTokenTTL time.Duration
+	// хранилище данных, если не задано - создаётся suftfake.NewServer()
+	Fake *suftfake.Server
+}
+
+type user struct {
+	password   string
+	employeeId int
+}
+
+type token struct {
+	employeeId int
+	expires    time.Time
+}
+
+// Server - запущенный HTTP-сервер. Методы можно вызывать из нескольких горутин одновременно
+type Server struct {
+	httpServer      *httptest.Server
+	fake            *suftfake.Server
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+
+	mu            sync.Mutex
+	users         map[string]*user
+	accessTokens  map[string]*token
+	refreshTokens map[string]*token
+	// сдвиг часов сервера, задаётся Advance
+	offset time.Duration
+}
+
+// NewServer запускает сервер, остановить его нужно методом Close
+func NewServer(options *Options) *Server {
+	s := &Server{
+		fake:            suftfake.NewServer(),
+		accessTokenTTL:  defaultAccessTokenTTL,
+		refreshTokenTTL: defaultRefreshTokenTTL,
+		users:           map[string]*user{},
+		accessTokens:    map[string]*token{},
+		refreshTokens:   map[string]*token{},
+	}
+	if options != nil {
+		if options.Fake != nil {
+			s.fake = options.Fake
+		}
+		if options.AccessTokenTTL != 0 {
+			s.accessTokenTTL = options.AccessTokenTTL
+		}
+		if options.RefreshTokenTTL != 0 {
+			s.refreshTokenTTL = options.RefreshTokenTTL
+		}
+	}
+	s.httpServer = httptest.NewServer(s)
+	return s
+}
+
+// URL возвращает адрес API для api.OptionsNC.SuftAPIURL и auth.Options.SuftAPIURL
+func (s *Server) URL() string {
+	return s.httpServer.URL + BasePath
+}
+
+func (s *Server) Close() {
+	s.httpServer.Close()
+}
+
+// Fake возвращает хранилище данных сервера для заполнения справочников,
+// проверок состояния и задания ошибок через FailNext и SetHook
+func (s *Server) Fake() *suftfake.Server {
+	return s.fake
+}
+
+// AddUser добавляет сотрудника, который может войти с email и password.
+// approverId - id согласующего, как в suftfake.Server.AddEmployee
+func (s *Server) AddUser(email string, password string, approverId int) api.Employee {
+	e := s.fake.AddEmployee(api.Employee{Email: email}, approverId)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[email] = &user{password: password, employeeId: e.Id}
+	return e
+}
+
+// Tokens выдаёт сотруднику новую пару токенов без запроса к серверу
+func (s *Server) Tokens(employeeId int) *auth.Token {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.issue(employeeId)
+}
+
+// Advance переводит часы сервера вперёд на d, чтобы проверить истечение токенов без ожидания
+func (s *Server) Advance(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.offset += d
+}
+
+// ExpireAccessTokens делает недействительными все выданные access-токены,
+// refresh-токены остаются действительными
+func (s *Server) ExpireAccessTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accessTokens = map[string]*token{}
+}
+
+func (s *Server) now() time.Time {
+	return time.Now().Add(s.offset)
+}
+
+// выдаёт пару токенов, вызывается под s.mu
+func (s *Server) issue(employeeId int) *auth.Token {
+	now := s.now()
+	accessToken := newTokenValue()
+	refreshToken := newTokenValue()
+	s.accessTokens[accessToken] = &token{employeeId: employeeId, expires: now.Add(s.accessTokenTTL)}
+	s.refreshTokens[refreshToken] = &token{employeeId: employeeId, expires: now.Add(s.refreshTokenTTL)}
+	return &auth.Token{AccessToken: accessToken, RefreshToken: refreshToken}
+}
+
+// id сотрудника по access-токену; false, если токен неизвестен или истёк
+func (s *Server) employeeByAccessToken(value string) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.accessTokens[value]
+	if !ok || !s.now().Before(t.expires) {
+		return 0, false
+	}
+	return t.employeeId, true
+}
+
+func newTokenValue() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,171 @@
+package sufttest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"suftsdk/internal/auth"
+	"suftsdk/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixture struct {
+	server  *Server
+	boss    api.Employee
+	user    api.Employee
+	periods []api.Period
+	project api.Project
+}
+
+func newFixture(t *testing.T, options *Options) *fixture {
+	server := NewServer(options)
+	t.Cleanup(server.Close)
+	boss := server.AddUser("boss@example.com", "boss", 0)
+	user := server.AddUser("user@example.com", "user", boss.Id)
+	return &fixture{
+		server:  server,
+		boss:    boss,
+		user:    user,
+		periods: server.Fake().AddWeeks(time.Date(2021, 2, 17, 0, 0, 0, 0, time.UTC), 3),
+		project: server.Fake().AddProject(api.Project{Code: "SUFT", Name: "Система учёта"}),
+	}
+}
+
+func (f *fixture) client(t *testing.T, email string, password string) *api.Client {
+	client, err := api.NewClient(email, password, &api.OptionsNC{SuftAPIURL: f.server.URL()})
+	require.NoError(t, err)
+	return client.(*api.Client)
+}
+
+func TestWorkflow(t *testing.T) {
+	f := newFixture(t, nil)
+	user := f.client(t, "user@example.com", "user")
+	boss := f.client(t, "boss@example.com", "boss")
+
+	schedule, err := user.AddSchedule(api.PeriodId(f.periods[0].Id))
+	require.NoError(t, err)
+	assert.Equal(t, f.user.Id, schedule.Author.Id)
+	loggingTime, err := schedule.AddLoggingTime(&api.AddLoggingTime{Day1Time: 8, ProjectId: f.project.Id, Task: "SUFT-1"})
+	require.NoError(t, err)
+	loggingTime, err = loggingTime.Update(&api.EditLoggingTime{Day1Time: 6, Day2Time: 2, ProjectId: f.project.Id, Task: "SUFT-1"})
+	require.NoError(t, err)
+	assert.Equal(t, 8.0, loggingTime.TotalHours())
+
+	loggingTimes, err := user.LoggingTimeList(api.ScheduleId(schedule.Id), nil)
+	require.NoError(t, err)
+	require.Len(t, loggingTimes, 1)
+	_, err = user.SubmitForApproveSchedule(api.ScheduleId(schedule.Id))
+	require.NoError(t, err)
+
+	schedules, err := boss.Schedules(&api.OptionsS{CreatorApprover: api.Approver})
+	require.NoError(t, err)
+	require.Len(t, schedules, 1)
+	_, err = boss.ApproveLoggingTime(api.ScheduleId(schedule.Id), api.LoggingTimeId(loggingTime.Id), "ok")
+	require.NoError(t, err)
+	stored, ok := f.server.Fake().Schedule(api.ScheduleId(schedule.Id))
+	require.True(t, ok)
+	assert.Equal(t, api.Approved, stored.StatusCode)
+}
+
+func TestReferences(t *testing.T) {
+	f := newFixture(t, nil)
+	user := f.client(t, "user@example.com", "user")
+
+	projects, err := user.Projects(&api.OptionsP{Search: "suft"})
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	assert.Equal(t, f.project.Id, projects[0].Id)
+
+	periods, err := user.Periods(&api.OptionsPD{StartDate: time.Date(2021, 2, 22, 0, 0, 0, 0, time.UTC)})
+	require.NoError(t, err)
+	require.Len(t, periods, 2)
+	assert.Equal(t, f.periods[1].Id, periods[0].Id)
+}
+
+func TestErrors(t *testing.T) {
+	f := newFixture(t, nil)
+	user := f.client(t, "user@example.com", "user")
+	boss := f.client(t, "boss@example.com", "boss")
+
+	_, err := user.DetailSchedule(12345)
+	assert.ErrorIs(t, err, api.ErrNotFound)
+	schedule, err := user.AddSchedule(api.PeriodId(f.periods[0].Id))
+	require.NoError(t, err)
+	_, err = user.AddSchedule(api.PeriodId(f.periods[0].Id))
+	assert.ErrorIs(t, err, api.ErrConflict)
+	_, err = boss.AddLoggingTime(api.ScheduleId(schedule.Id), &api.AddLoggingTime{Day1Time: 8, ProjectId: f.project.Id})
+	assert.ErrorIs(t, err, api.ErrForbidden)
+
+	_, err = api.NewClient("user@example.com", "wrong", &api.OptionsNC{SuftAPIURL: f.server.URL()})
+	assert.Error(t, err)
+}
+
+func TestExpiredAccessTokenIsRefreshed(t *testing.T) {
+	f := newFixture(t, &Options{AccessTokenTTL: time.Minute, RefreshTokenTTL: time.Hour})
+	user := f.client(t, "user@example.com", "user")
+	accessToken, refreshToken := user.Tokens()
+
+	f.server.Advance(2 * time.Minute)
+	_, err := user.Projects(nil)
+	require.NoError(t, err)
+	newAccessToken, newRefreshToken := user.Tokens()
+	assert.NotEqual(t, accessToken, newAccessToken)
+	assert.NotEqual(t, refreshToken, newRefreshToken)
+
+	// использованный refresh-токен больше не действует
+	_, err = auth.Refresh(refreshToken, &auth.Options{SuftAPIURL: f.server.URL()})
+	assert.ErrorIs(t, err, auth.ErrRefresh)
+}
+
+func TestExpiredRefreshToken(t *testing.T) {
+	f := newFixture(t, &Options{AccessTokenTTL: time.Minute, RefreshTokenTTL: time.Hour})
+	user := f.client(t, "user@example.com", "user")
+
+	f.server.Advance(2 * time.Hour)
+	_, err := user.Projects(nil)
+	assert.ErrorIs(t, err, api.ErrUnauthorized)
+}
+
+func TestExpireAccessTokens(t *testing.T) {
+	f := newFixture(t, nil)
+	user := f.client(t, "user@example.com", "user")
+	accessToken, _ := user.Tokens()
+
+	f.server.ExpireAccessTokens()
+	_, err := user.Schedules(nil)
+	require.NoError(t, err)
+	newAccessToken, _ := user.Tokens()
+	assert.NotEqual(t, accessToken, newAccessToken)
+}
+
+func TestTokens(t *testing.T) {
+	f := newFixture(t, nil)
+	tokens := f.server.Tokens(f.user.Id)
+	client := &api.Client{
+		BaseURL:      f.server.URL(),
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		HttpClient:   http.DefaultClient,
+	}
+	_, err := client.Schedules(nil)
+	assert.NoError(t, err)
+}
+
+func TestUnauthorizedResponse(t *testing.T) {
+	f := newFixture(t, nil)
+	resp, err := http.Get(f.server.URL() + api.SchedulesURN)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	errorBody := api.ErrorBody{}
+	require.NoError(t, json.Unmarshal(body, &errorBody))
+	assert.Equal(t, http.StatusUnauthorized, errorBody.Status)
+	assert.Equal(t, "/tools/suft/api/v1/api/v1/schedules", errorBody.Path)
+}